@@ -17,7 +17,7 @@ func main() {
 	if err := exec.Run(os.Args); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		exec.Close()
-		os.Exit(1)
+		os.Exit(executor.ExitCode(err))
 	}
 
 	exec.Close()
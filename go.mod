@@ -9,6 +9,9 @@ require (
 	github.com/gorilla/websocket v1.5.1
 	github.com/stretchr/testify v1.7.1
 	github.com/urfave/cli/v2 v2.27.1
+	golang.org/x/crypto v0.18.0
+	golang.org/x/net v0.20.0
+	golang.org/x/term v0.19.0
 	gopkg.in/yaml.v3 v3.0.1
 )
 
@@ -18,5 +21,5 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/russross/blackfriday/v2 v2.1.0 // indirect
 	github.com/xrash/smetrics v0.0.0-20231213231151-1d8dd44e695e // indirect
-	golang.org/x/net v0.20.0 // indirect
+	golang.org/x/sys v0.19.0 // indirect
 )
@@ -0,0 +1,167 @@
+// Package colors contains Minecraft color codes and their conversions to
+// terminal escape sequences and HTML markup.
+package colors
+
+import (
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+)
+
+// Marker is the prefix of Minecraft color codes, e.g. §a.
+const Marker = '§'
+
+// ANSIReset resets terminal colors.
+const ANSIReset = "\033[0m"
+
+// Supported ANSI color modes.
+const (
+	Mode16        = "16"
+	Mode256       = "256"
+	ModeTrueColor = "truecolor"
+	ModeNone      = "none"
+)
+
+// Color contains representations of a Minecraft color code.
+type Color struct {
+	// ANSI is the terminal escape sequence.
+	ANSI string
+	// HTML is the hex color. It is empty for reset code.
+	HTML string
+}
+
+// Minecraft is the map of Minecraft color codes.
+var Minecraft = map[rune]Color{
+	'0': {ANSI: "\033[30m", HTML: "#000000"}, // Black
+	'1': {ANSI: "\033[34m", HTML: "#0000AA"}, // Dark Blue
+	'2': {ANSI: "\033[32m", HTML: "#00AA00"}, // Dark Green
+	'3': {ANSI: "\033[36m", HTML: "#00AAAA"}, // Dark Aqua
+	'4': {ANSI: "\033[31m", HTML: "#AA0000"}, // Dark Red
+	'5': {ANSI: "\033[35m", HTML: "#AA00AA"}, // Dark Purple
+	'6': {ANSI: "\033[33m", HTML: "#FFAA00"}, // Gold
+	'7': {ANSI: "\033[37m", HTML: "#AAAAAA"}, // Gray
+	'8': {ANSI: "\033[90m", HTML: "#555555"}, // Dark Gray
+	'9': {ANSI: "\033[94m", HTML: "#5555FF"}, // Blue
+	'a': {ANSI: "\033[92m", HTML: "#55FF55"}, // Green
+	'b': {ANSI: "\033[96m", HTML: "#55FFFF"}, // Aqua
+	'c': {ANSI: "\033[91m", HTML: "#FF5555"}, // Red
+	'd': {ANSI: "\033[95m", HTML: "#FF55FF"}, // Light Purple
+	'e': {ANSI: "\033[93m", HTML: "#FFFF55"}, // Yellow
+	'f': {ANSI: "\033[97m", HTML: "#FFFFFF"}, // White
+	'r': {ANSI: ANSIReset},                   // Reset
+}
+
+// ToANSI returns the terminal escape sequence of the color in the mode.
+// The 256 and truecolor modes are derived from the HTML color, the reset
+// code is the same in all modes. The none mode returns an empty string.
+func (c Color) ToANSI(mode string) string {
+	if mode == ModeNone {
+		return ""
+	}
+
+	if c.HTML == "" || (mode != Mode256 && mode != ModeTrueColor) {
+		return c.ANSI
+	}
+
+	r, g, b, ok := parseHex(c.HTML)
+	if !ok {
+		return c.ANSI
+	}
+
+	if mode == ModeTrueColor {
+		return fmt.Sprintf("\033[38;2;%d;%d;%dm", r, g, b)
+	}
+
+	return fmt.Sprintf("\033[38;5;%dm", 16+36*cubeLevel(r)+6*cubeLevel(g)+cubeLevel(b))
+}
+
+// parseHex parses #RRGGBB color.
+func parseHex(hex string) (r, g, b int, ok bool) {
+	const length = 7
+
+	if len(hex) != length || hex[0] != '#' {
+		return 0, 0, 0, false
+	}
+
+	v, err := strconv.ParseUint(hex[1:], 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+
+	return int(v >> 16 & 0xFF), int(v >> 8 & 0xFF), int(v & 0xFF), true
+}
+
+// cubeLevel returns the nearest level of the 6x6x6 color cube of the 256
+// color palette. The levels are 0, 95, 135, 175, 215 and 255.
+func cubeLevel(v int) int {
+	switch {
+	case v < 48:
+		return 0
+	case v < 115:
+		return 1
+	default:
+		return (v - 35) / 40
+	}
+}
+
+// ToHTML converts Minecraft color codes and their terminal escape sequences
+// to HTML span elements. The rest of the text is HTML escaped.
+func ToHTML(text string) string {
+	var (
+		result strings.Builder
+		plain  strings.Builder
+		open   bool
+	)
+
+	flush := func() {
+		result.WriteString(html.EscapeString(plain.String()))
+		plain.Reset()
+	}
+
+	apply := func(color Color) {
+		flush()
+
+		if open {
+			result.WriteString("</span>")
+			open = false
+		}
+
+		if color.HTML != "" {
+			result.WriteString(`<span style="color:` + color.HTML + `">`)
+			open = true
+		}
+	}
+
+	runes := []rune(text)
+
+outer:
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == Marker && i+1 < len(runes) {
+			if color, ok := Minecraft[runes[i+1]]; ok {
+				apply(color)
+				i++
+
+				continue
+			}
+		}
+
+		if runes[i] == '\033' {
+			rest := string(runes[i:])
+			for _, color := range Minecraft {
+				if strings.HasPrefix(rest, color.ANSI) {
+					apply(color)
+					i += len([]rune(color.ANSI)) - 1
+
+					continue outer
+				}
+			}
+		}
+
+		plain.WriteRune(runes[i])
+	}
+
+	apply(Color{})
+
+	return result.String()
+}
@@ -0,0 +1,54 @@
+package colors_test
+
+import (
+	"testing"
+
+	"github.com/crasssr/rcon-cli/internal/colors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToHTML(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"plain", "a < b", "a &lt; b"},
+		{"minecraft codes", "§aGreen§r plain", `<span style="color:#55FF55">Green</span> plain`},
+		{"ansi codes", "\033[91mRed\033[0m", `<span style="color:#FF5555">Red</span>`},
+		{"unclosed", "§6Gold §cRed", `<span style="color:#FFAA00">Gold </span><span style="color:#FF5555">Red</span>`},
+		{"unknown code", "§zText", "§zText"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, colors.ToHTML(tt.text))
+		})
+	}
+}
+
+func TestColor_ToANSI(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     rune
+		mode     string
+		expected string
+	}{
+		{"16", 'a', colors.Mode16, "\033[92m"},
+		{"256", 'a', colors.Mode256, "\033[38;5;83m"},
+		{"256 gold", '6', colors.Mode256, "\033[38;5;214m"},
+		{"truecolor", '6', colors.ModeTrueColor, "\033[38;2;255;170;0m"},
+		{"none", 'a', colors.ModeNone, ""},
+		{"reset", 'r', colors.ModeTrueColor, colors.ANSIReset},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, colors.Minecraft[tt.code].ToANSI(tt.mode))
+		})
+	}
+}
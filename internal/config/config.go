@@ -1,12 +1,16 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -14,10 +18,17 @@ import (
 // DefaultConfigName sets the default config file name.
 const DefaultConfigName = "rcon.yaml"
 
+// StdinConfigName is the config name to read the config from stdin.
+const StdinConfigName = "-"
+
 // DefaultConfigEnv is the name of the environment, which is taken
 // as default unless another value is passed.
 const DefaultConfigEnv = "default"
 
+// GlobalSection is the name of the config section with defaults shared by
+// all environments. It is not an environment itself.
+const GlobalSection = "global"
+
 var (
 	// ErrConfigValidation is when config validation completed with errors.
 	ErrConfigValidation = errors.New("config validation error")
@@ -25,6 +36,10 @@ var (
 	// ErrUnsupportedFileExt is returned when config file has an unsupported
 	// extension. Allowed extensions is `.json`, `.yml`, `.yaml`.
 	ErrUnsupportedFileExt = errors.New("unsupported file extension")
+
+	// ErrAmbiguousEnv is returned when environment name prefix matches
+	// several environments.
+	ErrAmbiguousEnv = errors.New("ambiguous environment")
 )
 
 // Config allows to take a remote server address and password from
@@ -39,13 +54,53 @@ var (
 //	password: "password"
 //
 // ```.
-type Config map[string]Session
+type Config map[string]Env
+
+// NewConfig finds and parses config files with remote server credentials.
+// Several files are merged in order: later files override earlier ones
+// per environment and field.
+func NewConfig(names ...string) (*Config, error) {
+	return NewConfigFrom(os.Stdin, names...)
+}
+
+// NewConfigFrom is like NewConfig but reads the config named
+// StdinConfigName from stdin reader.
+func NewConfigFrom(stdin io.Reader, names ...string) (*Config, error) {
+	return newConfig(stdin, false, names...)
+}
+
+// NewStrictConfigFrom is like NewConfigFrom but returns an error on unknown
+// fields of environments, e.g. misspelled keys or runtime options which are
+// set only by flags.
+func NewStrictConfigFrom(stdin io.Reader, names ...string) (*Config, error) {
+	return newConfig(stdin, true, names...)
+}
+
+func newConfig(stdin io.Reader, strict bool, names ...string) (*Config, error) {
+	if len(names) == 0 {
+		names = []string{""}
+	}
 
-// NewConfig finds and parses config file with remote server credentials.
-func NewConfig(name string) (*Config, error) {
 	cfg := new(Config)
-	if err := cfg.ParseFromFile(name); err != nil {
-		return nil, fmt.Errorf("parse file: %w", err)
+
+	for _, name := range names {
+		part := new(Config)
+
+		if name == StdinConfigName {
+			if err := part.parseReader(stdin, strict); err != nil {
+				return nil, fmt.Errorf("parse stdin: %w", err)
+			}
+
+			cfg.Merge(*part)
+
+			continue
+		}
+
+		if err := part.parseFile(name, strict); err != nil {
+			return nil, fmt.Errorf("parse file: %w", err)
+		}
+
+		cfg.Merge(*part)
 	}
 
 	if err := cfg.Validate(); err != nil {
@@ -58,17 +113,20 @@ func NewConfig(name string) (*Config, error) {
 // ParseFromFile reads a configuration file from disk and loads its contents into
 // the application's config structure. YAML and JSON files are supported.
 func (cfg *Config) ParseFromFile(name string) error {
+	return cfg.parseFile(name, false)
+}
+
+func (cfg *Config) parseFile(name string, strict bool) error {
 	if name != "" {
-		return cfg.parse(name)
+		return cfg.parse(name, strict)
 	}
 
-	home, err := filepath.Abs(filepath.Dir(os.Args[0]))
+	name, err := DefaultConfigPath()
 	if err != nil {
-		return fmt.Errorf("get abs path: %w", err)
+		return err
 	}
 
-	name = home + "/" + DefaultConfigName
-	if err = cfg.parse(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+	if err = cfg.parse(name, strict); err != nil && !errors.Is(err, os.ErrNotExist) {
 		return err
 	}
 
@@ -77,15 +135,55 @@ func (cfg *Config) ParseFromFile(name string) error {
 	return nil
 }
 
+// DefaultConfigPath returns the path of the default config file next to
+// the executable.
+func DefaultConfigPath() (string, error) {
+	home, err := filepath.Abs(filepath.Dir(os.Args[0]))
+	if err != nil {
+		return "", fmt.Errorf("get abs path: %w", err)
+	}
+
+	return home + "/" + DefaultConfigName, nil
+}
+
+// ParseFromReader reads the configuration from r. YAML and JSON are
+// supported as JSON is valid YAML.
+func (cfg *Config) ParseFromReader(r io.Reader) error {
+	return cfg.parseReader(r, false)
+}
+
+func (cfg *Config) parseReader(r io.Reader, strict bool) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("read: %w", err)
+	}
+
+	return unmarshalYAML(data, cfg, strict)
+}
+
+// Merge merges environments of other config into cfg. Non-empty fields
+// of other override fields of the same environment in cfg.
+func (cfg *Config) Merge(other Config) {
+	if *cfg == nil {
+		*cfg = Config{}
+	}
+
+	for name, env := range other {
+		merged := (*cfg)[name]
+		merged.Merge(env)
+		(*cfg)[name] = merged
+	}
+}
+
 // Validate validates the config fields.
 func (cfg *Config) Validate() error {
 	if cfg == nil {
 		return fmt.Errorf("%w: config is not set", ErrConfigValidation)
 	}
 
-	for key, ses := range *cfg {
-		switch ses.Type {
-		case "", ProtocolRCON, ProtocolTELNET, ProtocolWebRCON:
+	for key, env := range *cfg {
+		switch env.Type {
+		case "", ProtocolRCON, ProtocolTELNET, ProtocolTELNETS, ProtocolWebRCON, ProtocolHLDS:
 		default:
 			return fmt.Errorf("%w: unsupported type in %s environment", ErrConfigValidation, key)
 		}
@@ -94,7 +192,58 @@ func (cfg *Config) Validate() error {
 	return nil
 }
 
-func (cfg *Config) parse(name string) error {
+// Env returns the environment layered over the global section. Non-empty
+// fields of the environment override global defaults.
+func (cfg *Config) Env(name string) Env {
+	env := (*cfg)[GlobalSection]
+	env.Merge((*cfg)[name])
+
+	return env
+}
+
+// Names returns sorted names of the config environments. The global
+// section is not included.
+func (cfg *Config) Names() []string {
+	names := make([]string, 0, len(*cfg))
+	for name := range *cfg {
+		if name != GlobalSection {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// ResolveEnv returns the environment name matching name exactly or by
+// unique prefix. Unknown name is returned as is.
+func (cfg *Config) ResolveEnv(name string) (string, error) {
+	if _, ok := (*cfg)[name]; ok || name == "" {
+		return name, nil
+	}
+
+	var candidates []string
+
+	for env := range *cfg {
+		if env != GlobalSection && strings.HasPrefix(env, name) {
+			candidates = append(candidates, env)
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return name, nil
+	case 1:
+		return candidates[0], nil
+	default:
+		sort.Strings(candidates)
+
+		return name, fmt.Errorf("%w %q: matches %s", ErrAmbiguousEnv, name, strings.Join(candidates, ", "))
+	}
+}
+
+func (cfg *Config) parse(name string, strict bool) error {
 	file, err := os.ReadFile(name)
 	if err != nil {
 		return fmt.Errorf("read file: %w", err)
@@ -102,12 +251,30 @@ func (cfg *Config) parse(name string) error {
 
 	switch ext := path.Ext(name); ext {
 	case ".yml", ".yaml":
-		err = yaml.Unmarshal(file, cfg)
+		err = unmarshalYAML(file, cfg, strict)
 	case ".json":
-		err = json.Unmarshal(file, cfg)
+		dec := json.NewDecoder(bytes.NewReader(file))
+		if strict {
+			dec.DisallowUnknownFields()
+		}
+
+		err = dec.Decode(cfg)
 	default:
 		err = fmt.Errorf("%w %s", ErrUnsupportedFileExt, ext)
 	}
 
 	return err
 }
+
+// unmarshalYAML decodes YAML data to v. Unknown fields are errors in strict
+// mode. Empty data is not an error.
+func unmarshalYAML(data []byte, v interface{}, strict bool) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(strict)
+
+	if err := dec.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	return nil
+}
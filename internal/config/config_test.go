@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/crasssr/rcon-cli/internal/config"
 	"github.com/stretchr/testify/assert"
@@ -24,7 +26,7 @@ func TestNewConfig(t *testing.T) {
 		defer os.Remove(configFileName)
 
 		expected := config.Config{
-			"default": config.Session{Address: "", Password: "", Log: "rcon-test.log"},
+			"default": config.Env{Address: "", Password: "", Log: "rcon-test.log"},
 		}
 
 		cfg, err := config.NewConfig(configFileName)
@@ -39,7 +41,7 @@ func TestNewConfig(t *testing.T) {
 		defer os.Remove(configFileName)
 
 		expected := config.Config{
-			config.DefaultConfigEnv: config.Session{Address: "", Password: "", Log: DefaultTestLogName},
+			config.DefaultConfigEnv: config.Env{Address: "", Password: "", Log: DefaultTestLogName},
 		}
 
 		cfg, err := config.NewConfig(configFileName)
@@ -47,6 +49,34 @@ func TestNewConfig(t *testing.T) {
 		assert.Equal(t, &expected, cfg)
 	})
 
+	// Test durations are parsed from strings and nanoseconds.
+	t.Run("durations", func(t *testing.T) {
+		for name, body := range map[string]string{
+			"rcon-test-durations.json": `{"default": {"timeout": "30s", "log_flush_interval": 1000000000, "cache_ttl": "1m"}}`,
+			"rcon-test-durations.yaml": "default:\n  timeout: 30s\n  log_flush_interval: 1000000000\n  cache_ttl: \"1m\"\n",
+		} {
+			createFile(name, body)
+			defer os.Remove(name)
+
+			expected := config.Config{
+				config.DefaultConfigEnv: config.Env{
+					Timeout:          config.Duration(30 * time.Second),
+					LogFlushInterval: config.Duration(time.Second),
+					CacheTTL:         config.Duration(time.Minute),
+				},
+			}
+
+			cfg, err := config.NewConfig(name)
+			assert.NoError(t, err, name)
+			assert.Equal(t, &expected, cfg, name)
+		}
+
+		createFile("rcon-test-durations.json", `{"default": {"timeout": "30 seconds"}}`)
+
+		_, err := config.NewConfig("rcon-test-durations.json")
+		assert.ErrorContains(t, err, "invalid duration")
+	})
+
 	t.Run("file not exists", func(t *testing.T) {
 		cfg, err := config.NewConfig("nonexist.yaml")
 		if !errors.Is(err, os.ErrNotExist) {
@@ -101,11 +131,106 @@ func TestNewConfig(t *testing.T) {
 		assert.EqualError(t, err, "config validation error: unsupported type in default environment")
 
 		expected := config.Config{
-			config.DefaultConfigEnv: config.Session{Address: "", Password: "", Log: DefaultTestLogName, Type: "pigeon post"},
+			config.DefaultConfigEnv: config.Env{Address: "", Password: "", Log: DefaultTestLogName, Type: "pigeon post"},
+		}
+
+		assert.Equal(t, &expected, cfg)
+	})
+}
+
+func TestNewConfig_Merge(t *testing.T) {
+	sharedFileName := "rcon-test-shared.yaml"
+	createFile(sharedFileName, "default:\n  address: 127.0.0.1:16260\n  password: shared\n  log: shared.log\n"+
+		"rust:\n  address: 127.0.0.1:28016\n  type: web\n")
+	defer os.Remove(sharedFileName)
+
+	personalFileName := "rcon-test-personal.json"
+	createFile(personalFileName, `{"default": {"password": "personal"}}`)
+	defer os.Remove(personalFileName)
+
+	cfg, err := config.NewConfig(sharedFileName, personalFileName)
+	assert.NoError(t, err)
+
+	expected := config.Config{
+		config.DefaultConfigEnv: config.Env{Address: "127.0.0.1:16260", Password: "personal", Log: "shared.log"},
+		"rust":                  config.Env{Address: "127.0.0.1:28016", Type: config.ProtocolWebRCON},
+	}
+
+	assert.Equal(t, &expected, cfg)
+}
+
+func TestNewConfigFrom(t *testing.T) {
+	fileName := "rcon-test-shared.yaml"
+	createFile(fileName, "default:\n  address: 127.0.0.1:16260\n  password: shared\n")
+	defer os.Remove(fileName)
+
+	// Test config from stdin overrides the file.
+	t.Run("stdin", func(t *testing.T) {
+		stdin := strings.NewReader(`{"default": {"password": "stdin"}, "rust": {"address": "127.0.0.1:28016"}}`)
+
+		cfg, err := config.NewConfigFrom(stdin, fileName, config.StdinConfigName)
+		assert.NoError(t, err)
+
+		expected := config.Config{
+			config.DefaultConfigEnv: config.Env{Address: "127.0.0.1:16260", Password: "stdin"},
+			"rust":                  config.Env{Address: "127.0.0.1:28016"},
 		}
 
 		assert.Equal(t, &expected, cfg)
 	})
+
+	t.Run("invalid stdin", func(t *testing.T) {
+		_, err := config.NewConfigFrom(strings.NewReader("default: ["), config.StdinConfigName)
+		assert.Error(t, err)
+	})
+}
+
+func TestNewStrictConfigFrom(t *testing.T) {
+	yamlFileName := "rcon-test-strict.yaml"
+	createFile(yamlFileName, "default:\n  address: 127.0.0.1:16260\n  pasword: typo\n")
+	defer os.Remove(yamlFileName)
+
+	jsonFileName := "rcon-test-strict.json"
+	createFile(jsonFileName, `{"default": {"address": "127.0.0.1:16260", "pasword": "typo"}}`)
+	defer os.Remove(jsonFileName)
+
+	// Test unknown fields are ignored by default.
+	t.Run("not strict", func(t *testing.T) {
+		cfg, err := config.NewConfigFrom(nil, yamlFileName, jsonFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, &config.Config{config.DefaultConfigEnv: config.Env{Address: "127.0.0.1:16260"}}, cfg)
+	})
+
+	t.Run("unknown yaml field", func(t *testing.T) {
+		_, err := config.NewStrictConfigFrom(nil, yamlFileName)
+		assert.ErrorContains(t, err, "pasword")
+	})
+
+	t.Run("unknown json field", func(t *testing.T) {
+		_, err := config.NewStrictConfigFrom(nil, jsonFileName)
+		assert.ErrorContains(t, err, "pasword")
+	})
+
+	t.Run("unknown stdin field", func(t *testing.T) {
+		_, err := config.NewStrictConfigFrom(strings.NewReader("default:\n  pasword: typo\n"), config.StdinConfigName)
+		assert.ErrorContains(t, err, "pasword")
+	})
+
+	// Test runtime options set only by flags are not config keys.
+	t.Run("runtime field", func(t *testing.T) {
+		for _, key := range []string{"yes", "repeat", "halt_timeout", "output_file", "skip_errors"} {
+			_, err := config.NewStrictConfigFrom(strings.NewReader("default:\n  "+key+": 1\n"), config.StdinConfigName)
+			assert.ErrorContains(t, err, key)
+		}
+	})
+
+	t.Run("known fields", func(t *testing.T) {
+		stdin := strings.NewReader("default:\n  address: 127.0.0.1:16260\n  password: password\n")
+
+		cfg, err := config.NewStrictConfigFrom(stdin, config.StdinConfigName)
+		assert.NoError(t, err)
+		assert.Equal(t, &config.Config{config.DefaultConfigEnv: config.Env{Address: "127.0.0.1:16260", Password: "password"}}, cfg)
+	})
 }
 
 func TestConfig_Validate(t *testing.T) {
@@ -122,6 +247,61 @@ func TestConfig_Validate(t *testing.T) {
 	})
 }
 
+func TestConfig_Env(t *testing.T) {
+	cfg := config.Config{
+		config.GlobalSection: {Type: config.ProtocolTELNET, LogFormat: "json", NoColor: true},
+		"rust":               {Address: "127.0.0.1:28016", Type: config.ProtocolWebRCON},
+	}
+
+	assert.Equal(t, config.Env{
+		Address: "127.0.0.1:28016", Type: config.ProtocolWebRCON, LogFormat: "json", NoColor: true,
+	}, cfg.Env("rust"))
+	assert.Equal(t, config.Env{Type: config.ProtocolTELNET, LogFormat: "json", NoColor: true}, cfg.Env("missing"))
+	assert.Equal(t, []string{"rust"}, cfg.Names())
+}
+
+func TestCopyEnvFields(t *testing.T) {
+	dst := config.Session{Address: "127.0.0.1:16260", SSH: "admin@bastion", DenyPatterns: []string{"^stop"}, Yes: true}
+	config.CopyEnvFields(&dst, &config.Session{Address: "127.0.0.1:28016", Yes: false})
+
+	// Runtime fields are kept.
+	assert.Equal(t, config.Session{Address: "127.0.0.1:28016", Yes: true}, dst)
+}
+
+func TestDefaultProtocolTimeout(t *testing.T) {
+	assert.Equal(t, config.DefaultRCONTimeout, config.DefaultProtocolTimeout(""))
+	assert.Equal(t, config.DefaultRCONTimeout, config.DefaultProtocolTimeout(config.ProtocolRCON))
+	assert.Equal(t, config.DefaultTELNETTimeout, config.DefaultProtocolTimeout(config.ProtocolTELNET))
+	assert.Equal(t, config.DefaultWebRCONTimeout, config.DefaultProtocolTimeout(config.ProtocolWebRCON))
+}
+
+func TestConfig_ResolveEnv(t *testing.T) {
+	cfg := config.Config{"default": {}, "prod": {}, "prod-eu": {}, "staging": {}, "stage2": {}}
+
+	tests := []struct {
+		name     string
+		env      string
+		expected string
+	}{
+		{"exact", "prod", "prod"},
+		{"unique prefix", "prod-", "prod-eu"},
+		{"short prefix", "d", "default"},
+		{"unknown", "dev", "dev"},
+	}
+
+	for _, tt := range tests {
+		env, err := cfg.ResolveEnv(tt.env)
+		assert.NoError(t, err, tt.name)
+		assert.Equal(t, tt.expected, env, tt.name)
+	}
+
+	t.Run("ambiguous", func(t *testing.T) {
+		_, err := cfg.ResolveEnv("sta")
+		assert.ErrorIs(t, err, config.ErrAmbiguousEnv)
+		assert.EqualError(t, err, `ambiguous environment "sta": matches stage2, staging`)
+	})
+}
+
 func createFile(name, stringBody string) error {
 	file, err := os.Create(name)
 	if err != nil {
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Duration is the duration of the config file. It is written as a string
+// like "30s" in JSON and YAML, numbers are nanoseconds.
+type Duration time.Duration
+
+// UnmarshalJSON parses the duration from a JSON string or number.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	switch v := value.(type) {
+	case float64:
+		*d = Duration(v)
+
+		return nil
+	case string:
+		return d.parse(v)
+	default:
+		return fmt.Errorf("invalid duration %s", data)
+	}
+}
+
+// MarshalJSON writes the duration as a JSON string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalYAML parses the duration from a YAML string or integer.
+func (d *Duration) UnmarshalYAML(value *yaml.Node) error {
+	var text string
+	if err := value.Decode(&text); err != nil {
+		return err
+	}
+
+	if value.Tag == "!!int" {
+		n, err := strconv.ParseInt(text, 0, 64)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: %w", text, err)
+		}
+
+		*d = Duration(n)
+
+		return nil
+	}
+
+	return d.parse(text)
+}
+
+// MarshalYAML writes the duration as a YAML string.
+func (d Duration) MarshalYAML() (interface{}, error) {
+	return time.Duration(d).String(), nil
+}
+
+// parse sets the duration parsed from the string like "30s".
+func (d *Duration) parse(text string) error {
+	duration, err := time.ParseDuration(text)
+	if err != nil {
+		return fmt.Errorf("invalid duration: %w", err)
+	}
+
+	*d = Duration(duration)
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrEnvNotFound is returned when environment is missing in the config
+	// file.
+	ErrEnvNotFound = errors.New("environment not found")
+
+	// ErrEnvExists is returned when environment is renamed to the name of
+	// another environment.
+	ErrEnvExists = errors.New("environment already exists")
+)
+
+// RenameEnv renames the environment in the config file. Other
+// environments are preserved. Comments and order of YAML files are kept.
+func RenameEnv(name, env, newEnv string) error {
+	if newEnv == "" {
+		return fmt.Errorf("%w: empty environment name", ErrConfigValidation)
+	}
+
+	return editFile(name, env, newEnv)
+}
+
+// DeleteEnv removes the environment from the config file. Other
+// environments are preserved. Comments and order of YAML files are kept.
+func DeleteEnv(name, env string) error {
+	return editFile(name, env, "")
+}
+
+// editFile renames the environment to newEnv or deletes it if newEnv is
+// empty and writes the config file back.
+func editFile(name, env, newEnv string) error {
+	info, err := os.Stat(name)
+	if err != nil {
+		return fmt.Errorf("stat file: %w", err)
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return fmt.Errorf("read file: %w", err)
+	}
+
+	switch ext := path.Ext(name); ext {
+	case ".yml", ".yaml":
+		data, err = editYAML(data, env, newEnv)
+	case ".json":
+		data, err = editJSON(data, env, newEnv)
+	default:
+		err = fmt.Errorf("%w %s", ErrUnsupportedFileExt, ext)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(name, data, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("write file: %w", err)
+	}
+
+	return nil
+}
+
+// editYAML edits the environment in the YAML document tree, so comments
+// and order of the other nodes survive.
+func editYAML(data []byte, env, newEnv string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse: %w", err)
+	}
+
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%w %q", ErrEnvNotFound, env)
+	}
+
+	root := doc.Content[0]
+
+	index := -1
+
+	for i := 0; i < len(root.Content); i += 2 {
+		switch key := root.Content[i].Value; {
+		case key == env:
+			index = i
+		case key == newEnv && newEnv != "":
+			return nil, fmt.Errorf("%w %q", ErrEnvExists, newEnv)
+		}
+	}
+
+	if index < 0 {
+		return nil, fmt.Errorf("%w %q", ErrEnvNotFound, env)
+	}
+
+	if newEnv == "" {
+		root.Content = append(root.Content[:index], root.Content[index+2:]...)
+	} else {
+		root.Content[index].Value = newEnv
+	}
+
+	var buf bytes.Buffer
+
+	const indent = 2
+
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(indent)
+
+	if err := enc.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("encode: %w", err)
+	}
+
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("encode: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// editJSON edits the environment in the JSON object keeping the order of
+// the other environments.
+func editJSON(data []byte, env, newEnv string) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("parse: %w", errors.Join(ErrConfigValidation, err))
+	}
+
+	var (
+		buf   bytes.Buffer
+		found bool
+	)
+
+	buf.WriteByte('{')
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("parse: %w", err)
+		}
+
+		key, _ := tok.(string)
+
+		var value json.RawMessage
+		if err = dec.Decode(&value); err != nil {
+			return nil, fmt.Errorf("parse: %w", err)
+		}
+
+		switch {
+		case key == env:
+			found = true
+
+			if newEnv == "" {
+				continue
+			}
+
+			key = newEnv
+		case key == newEnv && newEnv != "":
+			return nil, fmt.Errorf("%w %q", ErrEnvExists, newEnv)
+		}
+
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+
+		js, _ := json.Marshal(key)
+		buf.Write(js)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	if !found {
+		return nil, fmt.Errorf("%w %q", ErrEnvNotFound, env)
+	}
+
+	buf.WriteByte('}')
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+		return nil, fmt.Errorf("encode: %w", err)
+	}
+
+	out.WriteByte('\n')
+
+	return out.Bytes(), nil
+}
@@ -0,0 +1,80 @@
+package config_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenameEnv(t *testing.T) {
+	// Test comments and order of YAML environments are preserved.
+	t.Run("yaml", func(t *testing.T) {
+		name := "rcon-test-rename.yaml"
+		defer os.Remove(name)
+
+		createFile(name, "# Servers.\nrust:\n  address: 127.0.0.1:28016 # Main.\n  type: web\ndefault:\n  address: 127.0.0.1:16260\n")
+
+		err := config.RenameEnv(name, "rust", "rust-main")
+		assert.NoError(t, err)
+
+		data, err := os.ReadFile(name)
+		assert.NoError(t, err)
+		assert.Equal(t, "# Servers.\nrust-main:\n  address: 127.0.0.1:28016 # Main.\n  type: web\ndefault:\n  address: 127.0.0.1:16260\n", string(data))
+
+		err = config.RenameEnv(name, "rust-main", "default")
+		assert.ErrorIs(t, err, config.ErrEnvExists)
+
+		err = config.RenameEnv(name, "rust", "rust-old")
+		assert.ErrorIs(t, err, config.ErrEnvNotFound)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		name := "rcon-test-rename.json"
+		defer os.Remove(name)
+
+		createFile(name, `{"rust": {"address": "127.0.0.1:28016"}, "default": {"address": "127.0.0.1:16260"}}`)
+
+		err := config.RenameEnv(name, "rust", "rust-main")
+		assert.NoError(t, err)
+
+		data, err := os.ReadFile(name)
+		assert.NoError(t, err)
+		assert.Equal(t, "{\n  \"rust-main\": {\n    \"address\": \"127.0.0.1:28016\"\n  },\n"+
+			"  \"default\": {\n    \"address\": \"127.0.0.1:16260\"\n  }\n}\n", string(data))
+	})
+}
+
+func TestDeleteEnv(t *testing.T) {
+	t.Run("yaml", func(t *testing.T) {
+		name := "rcon-test-delete.yaml"
+		defer os.Remove(name)
+
+		createFile(name, "rust:\n  address: 127.0.0.1:28016\ndefault:\n  address: 127.0.0.1:16260 # Local.\n")
+
+		err := config.DeleteEnv(name, "rust")
+		assert.NoError(t, err)
+
+		data, err := os.ReadFile(name)
+		assert.NoError(t, err)
+		assert.Equal(t, "default:\n  address: 127.0.0.1:16260 # Local.\n", string(data))
+
+		err = config.DeleteEnv(name, "rust")
+		assert.ErrorIs(t, err, config.ErrEnvNotFound)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		name := "rcon-test-delete.json"
+		defer os.Remove(name)
+
+		createFile(name, `{"rust": {"address": "127.0.0.1:28016"}, "default": {"address": "127.0.0.1:16260"}}`)
+
+		err := config.DeleteEnv(name, "default")
+		assert.NoError(t, err)
+
+		data, err := os.ReadFile(name)
+		assert.NoError(t, err)
+		assert.Equal(t, "{\n  \"rust\": {\n    \"address\": \"127.0.0.1:28016\"\n  }\n}\n", string(data))
+	})
+}
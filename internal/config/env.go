@@ -0,0 +1,78 @@
+package config
+
+import (
+	"reflect"
+)
+
+// Env contains the settings of the config environment. Only these keys are
+// allowed in the config file, runtime options of Session are set by flags.
+type Env struct {
+	Address   string   `json:"address" yaml:"address"`
+	Password  string   `json:"password" yaml:"password"`
+	Addresses []string `json:"addresses" yaml:"addresses"`
+	Type      string   `json:"type" yaml:"type"`
+	// Game selects the preset of the protocol, default port and response
+	// processing.
+	Game    string   `json:"game" yaml:"game"`
+	Timeout Duration `json:"timeout" yaml:"timeout"`
+	// Proxy and SSH tunnel the connection, see Session.
+	Proxy         string `json:"proxy" yaml:"proxy"`
+	SSH           string `json:"ssh" yaml:"ssh"`
+	SSHKey        string `json:"ssh_key" yaml:"ssh_key"`
+	SSHKnownHosts string `json:"ssh_known_hosts" yaml:"ssh_known_hosts"`
+	TLSCA         string `json:"tls_ca" yaml:"tls_ca"`
+	// Log settings, see Session.
+	Log              string   `json:"log" yaml:"log"`
+	LogFormat        string   `json:"log_format" yaml:"log_format"`
+	LogTemplate      string   `json:"log_template" yaml:"log_template"`
+	LogFlushInterval Duration `json:"log_flush_interval" yaml:"log_flush_interval"`
+	LogSync          bool     `json:"log_sync" yaml:"log_sync"`
+	// Color settings, see Session.
+	NoColor    bool   `json:"no_color" yaml:"no_color"`
+	ForceColor bool   `json:"force_color" yaml:"force_color"`
+	ColorMode  string `json:"color_mode" yaml:"color_mode"`
+	// Command filters, confirmation and cache, see Session.
+	DenyPatterns []string `json:"deny_patterns" yaml:"deny_patterns"`
+	AllowOnly    []string `json:"allow_only" yaml:"allow_only"`
+	Destructive  []string `json:"destructive" yaml:"destructive"`
+	CacheTTL     Duration `json:"cache_ttl" yaml:"cache_ttl"`
+	Cacheable    []string `json:"cacheable" yaml:"cacheable"`
+}
+
+// Merge overrides fields of the environment with non-empty fields of other.
+// Note that false boolean fields of other don't override true ones.
+func (e *Env) Merge(other Env) {
+	mergeFields(reflect.ValueOf(e).Elem(), reflect.ValueOf(other))
+}
+
+// Masked returns a copy of the environment with masked password.
+func (e *Env) Masked() Env {
+	masked := *e
+	if masked.Password != "" {
+		masked.Password = MaskedPassword
+	}
+
+	return masked
+}
+
+// CopyEnvFields sets the fields of dst session which can be set by
+// the config environment to the values of src session.
+func CopyEnvFields(dst *Session, src *Session) {
+	dstValue, srcValue := reflect.ValueOf(dst).Elem(), reflect.ValueOf(src).Elem()
+
+	envType := reflect.TypeOf(Env{})
+	for i := 0; i < envType.NumField(); i++ {
+		name := envType.Field(i).Name
+		dstValue.FieldByName(name).Set(srcValue.FieldByName(name))
+	}
+}
+
+// mergeFields sets fields of dst struct to non-zero fields of src struct of
+// the same type.
+func mergeFields(dst reflect.Value, src reflect.Value) {
+	for i := 0; i < src.NumField(); i++ {
+		if field := src.Field(i); !field.IsZero() {
+			dst.Field(i).Set(field)
+		}
+	}
+}
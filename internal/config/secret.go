@@ -0,0 +1,92 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Supported secret reference schemes.
+const (
+	SecretSchemeEnv  = "env"
+	SecretSchemeFile = "file"
+)
+
+var (
+	// ErrSecretNotFound is returned when a secret reference points to
+	// an environment variable that is not set.
+	ErrSecretNotFound = errors.New("secret not found")
+
+	// ErrUnsupportedSecretScheme is returned when a secret reference has
+	// a known scheme prefix without a registered resolver.
+	ErrUnsupportedSecretScheme = errors.New("unsupported secret scheme")
+)
+
+// SecretResolver resolves a secret reference without the scheme prefix
+// to the secret value.
+type SecretResolver func(ref string) (string, error)
+
+// secretResolvers contains resolvers for the registered secret schemes.
+var secretResolvers = map[string]SecretResolver{
+	SecretSchemeEnv:  resolveEnvSecret,
+	SecretSchemeFile: resolveFileSecret,
+}
+
+// reservedSecretSchemes contains schemes that are recognized as secret
+// references but have no built-in resolver yet.
+var reservedSecretSchemes = []string{"vault"}
+
+// RegisterSecretResolver adds or replaces the resolver for the scheme.
+func RegisterSecretResolver(scheme string, resolver SecretResolver) {
+	secretResolvers[scheme] = resolver
+}
+
+// ResolveSecret returns the secret value for the reference in the form
+// scheme:ref, e.g. env:RCON_PASSWORD takes the password from the
+// environment variable and file:/run/secrets/rcon reads it from the file.
+// Values without a known scheme prefix are returned as is.
+func ResolveSecret(value string) (string, error) {
+	scheme, ref, found := strings.Cut(value, ":")
+	if !found {
+		return value, nil
+	}
+
+	if resolver, ok := secretResolvers[scheme]; ok {
+		secret, err := resolver(ref)
+		if err != nil {
+			return "", fmt.Errorf("resolve %s secret: %w", scheme, err)
+		}
+
+		return secret, nil
+	}
+
+	for _, reserved := range reservedSecretSchemes {
+		if scheme == reserved {
+			return "", fmt.Errorf("%w %s", ErrUnsupportedSecretScheme, scheme)
+		}
+	}
+
+	return value, nil
+}
+
+// resolveEnvSecret returns the value of the environment variable.
+func resolveEnvSecret(ref string) (string, error) {
+	secret, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("%w: environment variable %s is not set", ErrSecretNotFound, ref)
+	}
+
+	return secret, nil
+}
+
+// resolveFileSecret returns the content of the file without trailing
+// line breaks.
+func resolveFileSecret(ref string) (string, error) {
+	file, err := os.ReadFile(ref)
+	if err != nil {
+		return "", fmt.Errorf("read file: %w", err)
+	}
+
+	return strings.TrimRight(string(file), "\r\n"), nil
+}
@@ -0,0 +1,51 @@
+package config_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveSecret(t *testing.T) {
+	t.Run("plain value", func(t *testing.T) {
+		secret, err := config.ResolveSecret("pass:word")
+		assert.NoError(t, err)
+		assert.Equal(t, "pass:word", secret)
+	})
+
+	t.Run("env scheme", func(t *testing.T) {
+		t.Setenv("RCON_TEST_SECRET", "password")
+
+		secret, err := config.ResolveSecret("env:RCON_TEST_SECRET")
+		assert.NoError(t, err)
+		assert.Equal(t, "password", secret)
+	})
+
+	t.Run("env scheme not set", func(t *testing.T) {
+		_, err := config.ResolveSecret("env:RCON_TEST_SECRET_NOT_SET")
+		assert.True(t, errors.Is(err, config.ErrSecretNotFound))
+	})
+
+	t.Run("file scheme", func(t *testing.T) {
+		secretFileName := "rcon-test-secret.txt"
+		createFile(secretFileName, "password\n")
+		defer os.Remove(secretFileName)
+
+		secret, err := config.ResolveSecret("file:" + secretFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, "password", secret)
+	})
+
+	t.Run("file scheme not exists", func(t *testing.T) {
+		_, err := config.ResolveSecret("file:nonexist.txt")
+		assert.True(t, errors.Is(err, os.ErrNotExist))
+	})
+
+	t.Run("unsupported scheme", func(t *testing.T) {
+		_, err := config.ResolveSecret("vault:secret/rcon")
+		assert.EqualError(t, err, "unsupported secret scheme vault")
+	})
+}
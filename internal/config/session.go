@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"reflect"
 	"time"
 )
 
@@ -11,27 +12,226 @@ import (
 const (
 	ProtocolRCON    = "rcon"
 	ProtocolTELNET  = "telnet"
+	ProtocolTELNETS = "telnets"
 	ProtocolWebRCON = "web"
+	ProtocolHLDS    = "hlds"
 )
 
 // DefaultProtocol contains the default protocol for connecting to a
 // remote server.
 const DefaultProtocol = ProtocolRCON
 
-// DefaultTimeout contains the default dial and execute timeout.
-const DefaultTimeout = 10 * time.Second
+// MaskedPassword replaces password in printed sessions.
+const MaskedPassword = "********"
 
-// Session contains details for making a request on a remote server.
+// Default dial and execute timeouts of the protocols. WebRCON handshake
+// over HTTP is slower than raw TCP protocols.
+const (
+	DefaultRCONTimeout    = 10 * time.Second
+	DefaultTELNETTimeout  = 10 * time.Second
+	DefaultWebRCONTimeout = 20 * time.Second
+)
+
+// DefaultTimeout contains the default dial and execute timeout of
+// the default protocol.
+const DefaultTimeout = DefaultRCONTimeout
+
+// DefaultProtocolTimeout returns the default dial and execute timeout of
+// the protocol. Empty protocol is treated as the default one.
+func DefaultProtocolTimeout(protocol string) time.Duration {
+	switch protocol {
+	case ProtocolTELNET, ProtocolTELNETS:
+		return DefaultTELNETTimeout
+	case ProtocolWebRCON:
+		return DefaultWebRCONTimeout
+	default:
+		return DefaultRCONTimeout
+	}
+}
+
+// Session contains details for making a request on a remote server. It is
+// built from flags and the config environment (see Env).
 type Session struct {
 	Address  string `json:"address" yaml:"address"`
 	Password string `json:"password" yaml:"password"`
+	// Addresses are failover endpoints which are tried in order until one
+	// authenticates. Address is set to the used endpoint.
+	Addresses []string `json:"addresses" yaml:"addresses"`
+	// Proxy is the URL of SOCKS5 or HTTP proxy the connection is routed
+	// through, e.g. socks5://127.0.0.1:1080.
+	Proxy string `json:"proxy" yaml:"proxy"`
+	// SSH is the user@host[:port] of SSH server the connection is tunneled
+	// through. SSHKey is the private key, SSH agent is used if it is empty.
+	// The server key is verified with SSHKnownHosts or ~/.ssh/known_hosts.
+	SSH           string `json:"ssh" yaml:"ssh"`
+	SSHKey        string `json:"ssh_key" yaml:"ssh_key"`
+	SSHKnownHosts string `json:"ssh_known_hosts" yaml:"ssh_known_hosts"`
+	// TLSCA is the PEM file of certificates verifying the telnets server,
+	// system roots are used if it is empty. TLSInsecure skips verification.
+	TLSCA       string `json:"tls_ca" yaml:"tls_ca"`
+	TLSInsecure bool   `json:"tls_insecure" yaml:"tls_insecure"`
 	// Log is the name of the file to which requests will be logged.
 	// If not specified, no logging will be performed.
-	Log        string        `json:"log" yaml:"log"`
-	Type       string        `json:"type" yaml:"type"`
-	SkipErrors bool          `json:"skip_errors" yaml:"skip_errors"`
-	Timeout    time.Duration `json:"timeout" yaml:"timeout"`
-	Variables  bool          `json:"-" yaml:"-"`
+	Log  string `json:"log" yaml:"log"`
+	Type string `json:"type" yaml:"type"`
+	// LogFormat is the format of the log file: text or html.
+	LogFormat string `json:"log_format" yaml:"log_format"`
+	// NoLogMkdir disables creating of missing directories of the log file.
+	NoLogMkdir bool `json:"no_log_mkdir" yaml:"no_log_mkdir"`
+	// LogTemplate is Go template of the log line. It overrides LogFormat.
+	LogTemplate string `json:"log_template" yaml:"log_template"`
+	// LogFlushInterval keeps the log file open and flushes buffered
+	// records on the interval and on exit. Zero opens the file for every
+	// record. LogSync writes and syncs every record to disk at once.
+	LogFlushInterval time.Duration `json:"log_flush_interval" yaml:"log_flush_interval"`
+	LogSync          bool          `json:"log_sync" yaml:"log_sync"`
+	// Game enables game specific response processing, e.g. surfacing
+	// errors reported inline in the response.
+	Game string `json:"game" yaml:"game"`
+	// OutputFormat is the format of printed responses: text or json.
+	OutputFormat string        `json:"output_format" yaml:"output_format"`
+	SkipErrors   bool          `json:"skip_errors" yaml:"skip_errors"`
+	Timeout      time.Duration `json:"timeout" yaml:"timeout"`
+	// HaltTimeout is the maximum duration of the whole run including dial
+	// and all commands. If not specified, the run is not limited.
+	HaltTimeout time.Duration `json:"halt_timeout" yaml:"halt_timeout"`
+	// WarnSlow is the command duration after which a warning is printed
+	// to stderr.
+	WarnSlow time.Duration `json:"warn_slow" yaml:"warn_slow"`
+	// Repeat is the interval to repeat commands with. If not specified,
+	// commands are executed once.
+	Repeat      time.Duration `json:"repeat" yaml:"repeat"`
+	RepeatCount int           `json:"repeat_count" yaml:"repeat_count"`
+	// OnChange enables printing of repeated command response only when it
+	// differs from the previous one.
+	OnChange bool `json:"on_change" yaml:"on_change"`
+	// Diff enables printing of line-level diff of changed repeated command
+	// response instead of the whole response.
+	Diff bool `json:"diff" yaml:"diff"`
+	// NativeTelnet enables the built-in interactive loop of the telnet
+	// library instead of the common one.
+	NativeTelnet bool `json:"native_telnet" yaml:"native_telnet"`
+	// ResponseTo is the name of the standard stream for responses: stdout
+	// or stderr. Responses are written to stdout if not specified.
+	ResponseTo string `json:"response_to" yaml:"response_to"`
+	// NoColor disables colors in responses and diffs.
+	NoColor bool `json:"no_color" yaml:"no_color"`
+	// ForceColor keeps colors when the output is not a color terminal.
+	ForceColor bool `json:"force_color" yaml:"force_color"`
+	// ColorMode is the palette of ANSI colors: 16, 256, truecolor or none.
+	ColorMode string `json:"color_mode" yaml:"color_mode"`
+	// Rate is the maximum number of commands sent per second. If not
+	// specified, commands are not throttled.
+	Rate float64 `json:"rate" yaml:"rate"`
+	// TrimPrefix and TrimSuffix are removed from the response. With PerLine
+	// they are removed from every line of the response.
+	TrimPrefix string `json:"trim_prefix" yaml:"trim_prefix"`
+	TrimSuffix string `json:"trim_suffix" yaml:"trim_suffix"`
+	PerLine    bool   `json:"per_line" yaml:"per_line"`
+	// DenyPatterns blocks commands matching any of regular expressions.
+	DenyPatterns []string `json:"deny_patterns" yaml:"deny_patterns"`
+	// AllowOnly permits only commands matching any of regular expressions.
+	AllowOnly []string `json:"allow_only" yaml:"allow_only"`
+	// NoAuth connects without sending the password. It is supported only
+	// by protocols which do not mandate authentication.
+	NoAuth bool `json:"no_auth" yaml:"no_auth"`
+	// LogOnly disables printing of responses. Requests and responses are
+	// written only to the log file.
+	LogOnly bool `json:"log_only" yaml:"log_only"`
+	// NoWait treats the connection closed or timed out after sending
+	// the command as success. It is used for fire-and-forget commands.
+	NoWait bool `json:"no_wait" yaml:"no_wait"`
+	// Destructive extends the list of destructive command keywords which
+	// ask for confirmation before sending. Yes disables the confirmation.
+	Destructive []string `json:"destructive" yaml:"destructive"`
+	Yes         bool     `json:"yes" yaml:"yes"`
+	// StripLeadingSlash removes leading slash from commands before sending.
+	StripLeadingSlash bool `json:"strip_leading_slash" yaml:"strip_leading_slash"`
+	// OutputFile receives a copy of the printed output. It is truncated
+	// unless OutputAppend is set.
+	OutputFile   string `json:"output_file" yaml:"output_file"`
+	OutputAppend bool   `json:"output_append" yaml:"output_append"`
+	// Pager pipes responses longer than the terminal through the pager in
+	// Interactive mode.
+	Pager bool `json:"pager" yaml:"pager"`
+	// JSONPath selects values of JSON responses to print, e.g.
+	// .players[*].name.
+	JSONPath string `json:"jsonpath" yaml:"jsonpath"`
+	// StripEcho removes the first response line if it repeats the command.
+	// The match is case-sensitive unless StripEchoIgnoreCase is set.
+	StripEcho           bool `json:"strip_echo" yaml:"strip_echo"`
+	StripEchoIgnoreCase bool `json:"strip_echo_ignore_case" yaml:"strip_echo_ignore_case"`
+	// Labels prints each command as a header above its response.
+	Labels bool `json:"labels" yaml:"labels"`
+	// LastOnly prints only the response of the last command.
+	LastOnly bool `json:"last_only" yaml:"last_only"`
+	// Chaining splits commands by && and ; operators executed like in
+	// shell.
+	Chaining bool `json:"chaining" yaml:"chaining"`
+	// Nonce is the comment marker of the server, e.g. "//". The comment
+	// with a unique value is appended to every command to bypass caches
+	// keyed by the command.
+	Nonce string `json:"nonce" yaml:"nonce"`
+	// PrintSent prints the command payload to stderr exactly as it is
+	// passed to the client, in hex bytes if Hex is set.
+	PrintSent bool `json:"print_sent" yaml:"print_sent"`
+	Hex       bool `json:"hex" yaml:"hex"`
+	// Progress prints the command to stderr before it is sent so long
+	// batches show what is running.
+	Progress bool `json:"progress" yaml:"progress"`
+	// MergeResponses joins responses of all commands with a newline and
+	// prints them as the result of one command.
+	MergeResponses bool `json:"merge_responses" yaml:"merge_responses"`
+	// LineEnding is appended to commands before sending: lf, crlf or cr.
+	// Commands are sent as is if not specified.
+	LineEnding string `json:"line_ending" yaml:"line_ending"`
+	// ReconnectOnEmpty re-dials and retries the command once when it
+	// returns an empty response without error.
+	ReconnectOnEmpty bool `json:"reconnect_on_empty" yaml:"reconnect_on_empty"`
+	// TimeoutRetries is the number of times the timed out command
+	// is re-sent. Connection errors are not retried.
+	TimeoutRetries int `json:"command_timeout_retries" yaml:"command_timeout_retries"`
+	// RetryBudget caps the total number of command retries of the run.
+	// Retries are not limited if not specified.
+	RetryBudget int `json:"retry_budget" yaml:"retry_budget"`
+	// MultiPacket enables collecting of RCON responses split into several
+	// packets with the terminator packet.
+	MultiPacket bool `json:"multi_packet" yaml:"multi_packet"`
+	// Flatten joins lines of the response with FlattenSeparator so every
+	// response is printed as a single line.
+	Flatten          bool   `json:"flatten" yaml:"flatten"`
+	FlattenSeparator string `json:"flatten_separator" yaml:"flatten_separator"`
+	// Transcript is the name of the file to which the transcript of
+	// interactive session is written on exit.
+	Transcript string `json:"transcript" yaml:"transcript"`
+	// NoBanner disables the banner with the target address printed at the
+	// start of interactive mode.
+	NoBanner bool `json:"no_banner" yaml:"no_banner"`
+	// InteractiveKeepalive is the idle period in interactive mode after
+	// which KeepaliveCommand is sent to keep the connection warm.
+	InteractiveKeepalive time.Duration `json:"interactive_keepalive" yaml:"interactive_keepalive"`
+	KeepaliveCommand     string        `json:"keepalive_command" yaml:"keepalive_command"`
+	// CacheTTL is the lifetime of cached responses of Cacheable commands.
+	// Responses are not cached if not specified.
+	CacheTTL  time.Duration `json:"cache_ttl" yaml:"cache_ttl"`
+	Cacheable []string      `json:"cacheable" yaml:"cacheable"`
+	Variables bool          `json:"-" yaml:"-"`
+}
+
+// Merge overrides fields of the session with non-empty fields of other.
+// Note that false boolean fields of other don't override true ones.
+func (s *Session) Merge(other Session) {
+	mergeFields(reflect.ValueOf(s).Elem(), reflect.ValueOf(other))
+}
+
+// Masked returns a copy of the session with masked password.
+func (s *Session) Masked() Session {
+	masked := *s
+	if masked.Password != "" {
+		masked.Password = MaskedPassword
+	}
+
+	return masked
 }
 
 func (s *Session) Print(w io.Writer) error {
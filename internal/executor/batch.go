@@ -0,0 +1,223 @@
+package executor
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CommandFileComment is the prefix of comment lines in command files.
+const CommandFileComment = "#"
+
+// sectionRegexp matches section marker line `[name]` in command files.
+var sectionRegexp = regexp.MustCompile(`^\[\s*([^\]]*?)\s*\]$`)
+
+// ErrSectionNotFound is returned when command file has no commands in
+// the requested section.
+var ErrSectionNotFound = errors.New("section not found")
+
+// ErrNoGlobMatch is returned when command file glob matches no files.
+var ErrNoGlobMatch = errors.New("no command files match")
+
+// redirectRegexp matches command with output redirection suffix
+// `command > file` or `command >> file`.
+var redirectRegexp = regexp.MustCompile(`^(.*\S)\s+(>>?)\s*([^\s>]+)\s*$`)
+
+// Command contains command to execute on remote server and its options.
+type Command struct {
+	Text string
+	// Output is the name of the file to which the response will be written
+	// instead of the main writer. If not specified, no redirection
+	// will be performed.
+	Output string
+	// Append enables appending the response to the Output file instead
+	// of truncating it.
+	Append bool
+	// Section is the name of the command file section the command belongs
+	// to. Commands before the first section marker have empty section.
+	Section string
+	// Timeout overrides the session timeout for the command. The command
+	// is sent over a new connection dialed with this timeout.
+	Timeout time.Duration
+}
+
+// NewCommands converts command strings to commands without options.
+func NewCommands(commands ...string) []Command {
+	result := make([]Command, 0, len(commands))
+	for _, command := range commands {
+		result = append(result, Command{Text: command})
+	}
+
+	return result
+}
+
+// SplitCommands splits every argument by the delimiter to several commands.
+// Parts are trimmed and empty parts are skipped. Arguments are returned as
+// is if the delimiter is empty.
+func SplitCommands(args []string, delimiter string) []string {
+	if delimiter == "" {
+		return args
+	}
+
+	result := make([]string, 0, len(args))
+
+	for _, arg := range args {
+		for _, part := range strings.Split(arg, delimiter) {
+			if part = strings.TrimSpace(part); part != "" {
+				result = append(result, part)
+			}
+		}
+	}
+
+	return result
+}
+
+// ParseCommand parses command file line. The line can end with output
+// redirection to a file: `>` truncates the file, `>>` appends to it.
+func ParseCommand(line string) Command {
+	line = strings.TrimSpace(line)
+
+	matches := redirectRegexp.FindStringSubmatch(line)
+	if matches == nil {
+		return Command{Text: line}
+	}
+
+	return Command{Text: matches[1], Output: matches[3], Append: matches[2] == ">>"}
+}
+
+// ReadCommands reads commands from r one per line. Blank lines and lines
+// starting with # are ignored. Section marker `[name]` sets the section
+// of the following commands.
+func ReadCommands(r io.Reader) ([]Command, error) {
+	var (
+		commands []Command
+		section  string
+	)
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, CommandFileComment) {
+			continue
+		}
+
+		if matches := sectionRegexp.FindStringSubmatch(line); matches != nil {
+			section = matches[1]
+
+			continue
+		}
+
+		command := ParseCommand(line)
+		command.Section = section
+
+		commands = append(commands, command)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return commands, fmt.Errorf("read commands: %w", err)
+	}
+
+	return commands, nil
+}
+
+// ReadCommandFile reads commands from the file in the input format.
+func ReadCommandFile(name string, format string) ([]Command, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("open command file: %w", err)
+	}
+	defer file.Close()
+
+	return ReadCommandsFormat(file, format)
+}
+
+// WriteCommandFile writes commands to the file one per line, so it can be
+// read back with ReadCommandFile.
+func WriteCommandFile(name string, commands []string) error {
+	var buf strings.Builder
+	for _, command := range commands {
+		buf.WriteString(command + "\n")
+	}
+
+	if err := os.WriteFile(name, []byte(buf.String()), 0o644); err != nil {
+		return fmt.Errorf("write command file: %w", err)
+	}
+
+	return nil
+}
+
+// ReadCommandFiles reads commands from the files matching the glob pattern
+// in lexical order and concatenates them. The pattern without glob
+// characters is read as a single file. No matching files is an error
+// unless allowEmpty is set.
+func ReadCommandFiles(pattern string, format string, allowEmpty bool) ([]Command, error) {
+	if !strings.ContainsAny(pattern, "*?[") {
+		return ReadCommandFile(pattern, format)
+	}
+
+	names, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("command file glob: %w", err)
+	}
+
+	if len(names) == 0 && !allowEmpty {
+		return nil, fmt.Errorf("%w %q", ErrNoGlobMatch, pattern)
+	}
+
+	sort.Strings(names)
+
+	var commands []Command
+
+	for _, name := range names {
+		part, err := ReadCommandFile(name, format)
+		if err != nil {
+			return nil, err
+		}
+
+		commands = append(commands, part...)
+	}
+
+	return commands, nil
+}
+
+// openOutput opens the command redirection file.
+func openOutput(command Command) (*os.File, error) {
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if command.Append {
+		flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	}
+
+	const perm = 0o666
+
+	file, err := os.OpenFile(command.Output, flags, perm)
+	if err != nil {
+		return nil, fmt.Errorf("open output: %w", err)
+	}
+
+	return file, nil
+}
+
+// FilterSection returns commands of the section. Returns an error if there
+// are no commands in the section.
+func FilterSection(commands []Command, section string) ([]Command, error) {
+	var result []Command
+
+	for _, command := range commands {
+		if command.Section == section {
+			result = append(result, command)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrSectionNotFound, section)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,135 @@
+package executor_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/gorcon/rcon/rcontest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCommand(t *testing.T) {
+	t.Run("no redirect", func(t *testing.T) {
+		assert.Equal(t, executor.Command{Text: "list"}, executor.ParseCommand(" list "))
+	})
+
+	t.Run("truncate", func(t *testing.T) {
+		want := executor.Command{Text: "list", Output: "players.txt"}
+		assert.Equal(t, want, executor.ParseCommand("list > players.txt"))
+	})
+
+	t.Run("append", func(t *testing.T) {
+		want := executor.Command{Text: "say a > b", Output: "players.txt", Append: true}
+		assert.Equal(t, want, executor.ParseCommand("say a > b >> players.txt"))
+	})
+}
+
+func TestSplitCommands(t *testing.T) {
+	args := []string{"save-all ;; say saved;;", "list"}
+
+	assert.Equal(t, args, executor.SplitCommands(args, ""))
+	assert.Equal(t, []string{"save-all", "say saved", "list"}, executor.SplitCommands(args, ";;"))
+}
+
+func TestReadCommands(t *testing.T) {
+	r := strings.NewReader("# comment\n\nsave-all\nlist > players.txt\n")
+
+	commands, err := executor.ReadCommands(r)
+	assert.NoError(t, err)
+	assert.Equal(t, []executor.Command{
+		{Text: "save-all"},
+		{Text: "list", Output: "players.txt"},
+	}, commands)
+}
+
+func TestReadCommands_Sections(t *testing.T) {
+	r := strings.NewReader("say hi\n[setup]\n# comment\nsave-off\n\n[ main ]\nlist > players.txt\n")
+
+	commands, err := executor.ReadCommands(r)
+	assert.NoError(t, err)
+	assert.Equal(t, []executor.Command{
+		{Text: "say hi"},
+		{Text: "save-off", Section: "setup"},
+		{Text: "list", Output: "players.txt", Section: "main"},
+	}, commands)
+
+	t.Run("filter", func(t *testing.T) {
+		main, err := executor.FilterSection(commands, "main")
+		assert.NoError(t, err)
+		assert.Equal(t, []executor.Command{{Text: "list", Output: "players.txt", Section: "main"}}, main)
+
+		// Commands before the first section belong to the default section.
+		defaults, err := executor.FilterSection(commands, "")
+		assert.NoError(t, err)
+		assert.Equal(t, []executor.Command{{Text: "say hi"}}, defaults)
+
+		_, err = executor.FilterSection(commands, "teardown")
+		assert.ErrorIs(t, err, executor.ErrSectionNotFound)
+	})
+}
+
+func TestReadCommandFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	createFile(dir+"/b.rcon", "status\n")
+	createFile(dir+"/a.rcon", "list\nplayers\n")
+	createFile(dir+"/c.txt", "ignored\n")
+
+	// Test files are read in lexical order.
+	t.Run("glob", func(t *testing.T) {
+		commands, err := executor.ReadCommandFiles(dir+"/*.rcon", executor.InputFormatText, false)
+		assert.NoError(t, err)
+		assert.Equal(t, executor.NewCommands("list", "players", "status"), commands)
+	})
+
+	t.Run("single file", func(t *testing.T) {
+		commands, err := executor.ReadCommandFiles(dir+"/b.rcon", executor.InputFormatText, false)
+		assert.NoError(t, err)
+		assert.Equal(t, executor.NewCommands("status"), commands)
+	})
+
+	// Test no matching files.
+	t.Run("no match", func(t *testing.T) {
+		_, err := executor.ReadCommandFiles(dir+"/*.cfg", executor.InputFormatText, false)
+		assert.ErrorIs(t, err, executor.ErrNoGlobMatch)
+
+		commands, err := executor.ReadCommandFiles(dir+"/*.cfg", executor.InputFormatText, true)
+		assert.NoError(t, err)
+		assert.Empty(t, commands)
+	})
+}
+
+func TestExecuteCommands(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	t.Run("redirect output", func(t *testing.T) {
+		outputFileName := "rcon-test-output.txt"
+		defer os.Remove(outputFileName)
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password"}
+
+		err := app.ExecuteCommands(&w, ses, executor.ParseCommand("help > "+outputFileName))
+		assert.NoError(t, err)
+		assert.Empty(t, w.String())
+
+		err = app.ExecuteCommands(&w, ses, executor.ParseCommand("help >> "+outputFileName))
+		assert.NoError(t, err)
+
+		output, err := os.ReadFile(outputFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, strings.Count(string(output), "Can I help you?"))
+	})
+}
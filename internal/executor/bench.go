@@ -0,0 +1,257 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/crasssr/rcon-cli/internal/colors"
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+// Default bench options.
+const (
+	DefaultBenchConcurrency = 1
+	DefaultBenchDuration    = 10 * time.Second
+
+	// BenchDialBackoff is the pause before the worker dials again after
+	// failed dial, so unreachable server is not hammered.
+	BenchDialBackoff = 100 * time.Millisecond
+)
+
+// ErrInvalidBenchOptions is returned when bench options are out of range.
+var ErrInvalidBenchOptions = errors.New("invalid bench options")
+
+// BenchOptions contains load test parameters.
+type BenchOptions struct {
+	Command     string
+	Concurrency int
+	Duration    time.Duration
+}
+
+// BenchResult contains aggregated load test statistics.
+type BenchResult struct {
+	Requests   int
+	Errors     int
+	DialErrors int
+	Elapsed    time.Duration
+	P50        time.Duration
+	P90        time.Duration
+	P99        time.Duration
+}
+
+// Throughput returns the number of requests per second.
+func (res BenchResult) Throughput() float64 {
+	if res.Elapsed <= 0 {
+		return 0
+	}
+
+	return float64(res.Requests) / res.Elapsed.Seconds()
+}
+
+// ErrorRate returns the share of failed requests.
+func (res BenchResult) ErrorRate() float64 {
+	if res.Requests == 0 {
+		return 0
+	}
+
+	return float64(res.Errors) / float64(res.Requests)
+}
+
+// Print prints the result in human-readable form.
+func (res BenchResult) Print(w io.Writer) {
+	_, _ = fmt.Fprintf(w, "Requests:    %d\n", res.Requests)
+	_, _ = fmt.Fprintf(w, "Errors:      %d (%.2f%%)\n", res.Errors, res.ErrorRate()*100)
+	_, _ = fmt.Fprintf(w, "Dial errors: %d\n", res.DialErrors)
+	_, _ = fmt.Fprintf(w, "Elapsed:     %s\n", res.Elapsed.Round(time.Millisecond))
+	_, _ = fmt.Fprintf(w, "Throughput:  %.2f req/s\n", res.Throughput())
+	_, _ = fmt.Fprintf(w, "Latency:     p50 %s, p90 %s, p99 %s\n", res.P50, res.P90, res.P99)
+}
+
+// benchWorker contains latencies and errors of a single connection.
+type benchWorker struct {
+	latencies  []time.Duration
+	requests   int
+	errors     int
+	dialErrors int
+}
+
+// Bench sends the command repeatedly across opts.Concurrency connections
+// during opts.Duration and returns aggregated statistics. Every connection
+// uses its own client and session copy, so workers do not share state.
+// Latencies are collected for successful requests only, failed dials are
+// counted apart from requests. Requests bypass the response cache, the rate
+// limit and events, so every request reaches the server.
+func (executor *Executor) Bench(ses *config.Session, opts BenchOptions) (BenchResult, error) {
+	if opts.Command == "" {
+		return BenchResult{}, ErrCommandEmpty
+	}
+
+	if opts.Concurrency < 1 || opts.Duration <= 0 {
+		return BenchResult{}, fmt.Errorf("%w: concurrency %d, duration %s",
+			ErrInvalidBenchOptions, opts.Concurrency, opts.Duration)
+	}
+
+	if err := checkCommand(ses, opts.Command); err != nil {
+		return BenchResult{}, err
+	}
+
+	workers := make([]benchWorker, opts.Concurrency)
+	deadline := time.Now().Add(opts.Duration)
+	start := time.Now()
+
+	var wg sync.WaitGroup
+
+	for i := range workers {
+		wg.Add(1)
+
+		go func(worker *benchWorker, ses config.Session) {
+			defer wg.Done()
+
+			client := NewExecutor(nil, io.Discard, executor.version)
+			defer client.Close()
+
+			for time.Now().Before(deadline) {
+				if err := client.Dial(&ses); err != nil {
+					worker.dialErrors++
+
+					time.Sleep(min(BenchDialBackoff, time.Until(deadline)))
+
+					continue
+				}
+
+				worker.requests++
+
+				begin := time.Now()
+				if err := client.benchRequest(&ses, opts.Command); err != nil {
+					worker.errors++
+
+					// Reconnect after failed request.
+					client.dropClient()
+
+					continue
+				}
+
+				worker.latencies = append(worker.latencies, time.Since(begin))
+			}
+		}(&workers[i], *ses)
+	}
+
+	wg.Wait()
+
+	res := BenchResult{Elapsed: time.Since(start)}
+
+	var latencies []time.Duration
+
+	for _, worker := range workers {
+		res.Requests += worker.requests
+		res.Errors += worker.errors
+		res.DialErrors += worker.dialErrors
+		latencies = append(latencies, worker.latencies...)
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	res.P50 = percentile(latencies, 50)
+	res.P90 = percentile(latencies, 90)
+	res.P99 = percentile(latencies, 99)
+
+	return res, nil
+}
+
+// benchRequest sends the command on the dialed connection and checks the
+// response for game errors.
+func (executor *Executor) benchRequest(ses *config.Session, command string) error {
+	result, err := executor.client.Execute(terminate(ses, withNonce(ses, command)))
+	if err != nil {
+		return err
+	}
+
+	return processResponse(ses.Game, processColorCodes(strings.TrimSpace(result), colors.ModeNone))
+}
+
+// percentile returns p-th percentile of sorted latencies.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	return sorted[(len(sorted)-1)*p/100]
+}
+
+// benchCommand creates the bench subcommand.
+func (executor *Executor) benchCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "bench",
+		Usage:     "Load test the remote server by sending the command repeatedly",
+		UsageText: "bench --command list --concurrency 10 --duration 30s",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "command",
+				Usage:    "Command to send",
+				Required: true,
+			},
+			&cli.IntFlag{
+				Name:  "concurrency",
+				Usage: "Number of simultaneous connections",
+				Value: DefaultBenchConcurrency,
+			},
+			&cli.DurationFlag{
+				Name:  "duration",
+				Usage: "Duration of the load test",
+				Value: DefaultBenchDuration,
+			},
+			&cli.StringFlag{
+				Name:  "profile",
+				Usage: "Write CPU and memory pprof profiles of the run to files with the prefix",
+			},
+		},
+		Action: executor.bench,
+	}
+}
+
+// bench executes the bench subcommand.
+func (executor *Executor) bench(c *cli.Context) error {
+	ses, err := executor.NewSession(c)
+	if err != nil {
+		return err
+	}
+
+	if ses.Address == "" {
+		return ErrEmptyAddress
+	}
+
+	if ses.Password == "" && !ses.NoAuth {
+		return ErrEmptyPassword
+	}
+
+	if prefix := c.String("profile"); prefix != "" {
+		stop, err := startProfile(prefix)
+		if err != nil {
+			return err
+		}
+
+		defer func() {
+			if err := stop(); err != nil {
+				_, _ = fmt.Fprintln(executor.stderr, err)
+			}
+		}()
+	}
+
+	res, err := executor.Bench(ses, BenchOptions{
+		Command:     c.String("command"),
+		Concurrency: c.Int("concurrency"),
+		Duration:    c.Duration("duration"),
+	})
+	if err != nil {
+		return fmt.Errorf("bench: %w", err)
+	}
+
+	res.Print(executor.w)
+
+	return nil
+}
@@ -0,0 +1,126 @@
+package executor_test
+
+import (
+	"bytes"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/gorcon/rcon/rcontest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecutor_Bench(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	// Test invalid options.
+	t.Run("invalid options", func(t *testing.T) {
+		app := executor.NewExecutor(nil, nil, "")
+
+		_, err := app.Bench(&config.Session{}, executor.BenchOptions{Command: "help"})
+		assert.ErrorIs(t, err, executor.ErrInvalidBenchOptions)
+
+		_, err = app.Bench(&config.Session{}, executor.BenchOptions{Concurrency: 1, Duration: time.Second})
+		assert.ErrorIs(t, err, executor.ErrCommandEmpty)
+	})
+
+	// Test load across several connections.
+	t.Run("no error", func(t *testing.T) {
+		app := executor.NewExecutor(nil, nil, "")
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password"}
+
+		res, err := app.Bench(ses, executor.BenchOptions{Command: "help", Concurrency: 3, Duration: 200 * time.Millisecond})
+		assert.NoError(t, err)
+		assert.Greater(t, res.Requests, 0)
+		assert.Equal(t, 0, res.Errors)
+		assert.LessOrEqual(t, res.P50, res.P99)
+
+		w := bytes.Buffer{}
+		res.Print(&w)
+		assert.Contains(t, w.String(), "Throughput:")
+	})
+
+	// Test cached and throttled commands reach the server on every request.
+	t.Run("no cache", func(t *testing.T) {
+		var received atomic.Int64
+
+		server := rcontest.NewServer(
+			rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+			rcontest.SetCommandHandler(func(c *rcontest.Context) {
+				received.Add(1)
+				handlersRCON(c)
+			}),
+		)
+		defer server.Close()
+
+		app := executor.NewExecutor(nil, nil, "")
+
+		ses := &config.Session{
+			Address: server.Addr(), Password: "password",
+			CacheTTL: time.Minute, Cacheable: []string{"help"}, Rate: 1,
+		}
+
+		res, err := app.Bench(ses, executor.BenchOptions{Command: "help", Concurrency: 1, Duration: 200 * time.Millisecond})
+		assert.NoError(t, err)
+		assert.Greater(t, res.Requests, 2)
+		assert.Equal(t, 0, res.Errors)
+		assert.Equal(t, int64(res.Requests), received.Load())
+	})
+
+	// Test denied commands are refused.
+	t.Run("denied", func(t *testing.T) {
+		app := executor.NewExecutor(nil, nil, "")
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", DenyPatterns: []string{"^help$"}}
+
+		_, err := app.Bench(ses, executor.BenchOptions{Command: "help", Concurrency: 1, Duration: 100 * time.Millisecond})
+		assert.ErrorIs(t, err, executor.ErrCommandDenied)
+	})
+
+	// Test profiles are written by bench subcommand.
+	t.Run("profile", func(t *testing.T) {
+		prefix := "rcon-test-bench"
+		defer func() {
+			os.Remove(prefix + executor.ProfileCPUSuffix)
+			os.Remove(prefix + executor.ProfileMemorySuffix)
+		}()
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password", "bench", "--command=help", "--concurrency=1",
+			"--duration=100ms", "--profile="+prefix)
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "Throughput:")
+		assert.FileExists(t, prefix+executor.ProfileCPUSuffix)
+		assert.FileExists(t, prefix+executor.ProfileMemorySuffix)
+	})
+
+	// Test auth errors are counted.
+	t.Run("wrong password", func(t *testing.T) {
+		app := executor.NewExecutor(nil, nil, "")
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "wrong"}
+
+		res, err := app.Bench(ses, executor.BenchOptions{Command: "help", Concurrency: 1, Duration: 250 * time.Millisecond})
+		assert.NoError(t, err)
+
+		// Failed dials are not requests and are retried after backoff.
+		assert.Equal(t, 0, res.Requests)
+		assert.Greater(t, res.DialErrors, 0)
+		assert.LessOrEqual(t, res.DialErrors, 3)
+		assert.Zero(t, res.P99)
+	})
+}
@@ -0,0 +1,66 @@
+package executor
+
+import (
+	"time"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+)
+
+// cacheEntry is a cached command response with the raw response it was
+// processed from.
+type cacheEntry struct {
+	response string
+	raw      string
+	expires  time.Time
+}
+
+// cacheKey returns the cache key of the command on the server.
+func cacheKey(ses *config.Session, command string) string {
+	return ses.Address + "\x00" + command
+}
+
+// isCacheable returns true if the command response can be cached. Only
+// commands listed in the session are cached to avoid caching stateful ones.
+func isCacheable(ses *config.Session, command string) bool {
+	if ses.CacheTTL <= 0 {
+		return false
+	}
+
+	for _, cacheable := range ses.Cacheable {
+		if cacheable == command {
+			return true
+		}
+	}
+
+	return false
+}
+
+// cached returns the cached response if it is not expired.
+func (executor *Executor) cached(ses *config.Session, command string) (cacheEntry, bool) {
+	if !isCacheable(ses, command) {
+		return cacheEntry{}, false
+	}
+
+	entry, ok := executor.cache[cacheKey(ses, command)]
+	if !ok || time.Now().After(entry.expires) {
+		return cacheEntry{}, false
+	}
+
+	return entry, true
+}
+
+// store caches the response and the raw response if the command is
+// cacheable.
+func (executor *Executor) store(ses *config.Session, command string, response string, raw string) {
+	if !isCacheable(ses, command) {
+		return
+	}
+
+	if executor.cache == nil {
+		executor.cache = make(map[string]cacheEntry)
+	}
+
+	executor.cache[cacheKey(ses, command)] = cacheEntry{
+		response: response, raw: raw, expires: time.Now().Add(ses.CacheTTL),
+	}
+}
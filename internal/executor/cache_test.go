@@ -0,0 +1,50 @@
+package executor_test
+
+import (
+	"bytes"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/gorcon/rcon"
+	"github.com/gorcon/rcon/rcontest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecute_Cache(t *testing.T) {
+	var requests int32
+
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(func(c *rcontest.Context) {
+			n := atomic.AddInt32(&requests, 1)
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, fmt.Sprintf("response %d", n)).WriteTo(c.Conn())
+		}),
+	)
+	defer serverRCON.Close()
+
+	w := bytes.Buffer{}
+
+	app := executor.NewExecutor(nil, &w, "")
+	defer app.Close()
+
+	ses := &config.Session{
+		Address: serverRCON.Addr(), Password: "password", CacheTTL: 100 * time.Millisecond, Cacheable: []string{"version"},
+	}
+
+	// Test cacheable command is sent once within TTL.
+	err := app.Execute(&w, ses, "version", "version", "list", "list")
+	assert.NoError(t, err)
+	assert.Equal(t, "response 1\n--------\nresponse 1\n--------\nresponse 2\n--------\nresponse 3\n", w.String())
+
+	// Test expired response is requested again.
+	time.Sleep(150 * time.Millisecond)
+	w.Reset()
+
+	err = app.Execute(&w, ses, "version")
+	assert.NoError(t, err)
+	assert.Equal(t, "response 4\n", w.String())
+}
@@ -0,0 +1,64 @@
+package executor
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+)
+
+// Command chaining operators.
+const (
+	// ChainAnd runs the next command only if the previous one succeeded.
+	ChainAnd = "&&"
+
+	// ChainSeq runs the next command regardless of the previous result.
+	ChainSeq = ";"
+)
+
+// chainRegexp matches chaining operators with surrounding spaces.
+var chainRegexp = regexp.MustCompile(`\s*(&&|;)\s*`)
+
+// chainStep is the command of the chain and the operator preceding it.
+type chainStep struct {
+	operator string
+	text     string
+}
+
+// parseChain splits the command to steps by chaining operators. Empty
+// steps are skipped.
+func parseChain(text string) []chainStep {
+	var steps []chainStep
+
+	operator := ""
+	start := 0
+
+	add := func(end int) {
+		if part := strings.TrimSpace(text[start:end]); part != "" {
+			steps = append(steps, chainStep{operator: operator, text: part})
+		}
+	}
+
+	for _, loc := range chainRegexp.FindAllStringSubmatchIndex(text, -1) {
+		add(loc[0])
+
+		operator = text[loc[2]:loc[3]]
+		start = loc[1]
+	}
+
+	add(len(text))
+
+	return steps
+}
+
+// chainSteps splits the command by chaining operators if chaining is
+// enabled. The command is the only step otherwise.
+func chainSteps(ses *config.Session, command Command) []chainStep {
+	if ses.Chaining {
+		if steps := parseChain(command.Text); len(steps) > 0 {
+			return steps
+		}
+	}
+
+	return []chainStep{{text: command.Text}}
+}
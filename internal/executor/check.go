@@ -0,0 +1,121 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/urfave/cli/v2"
+)
+
+// DefaultCountRegex captures the first number of the response.
+const DefaultCountRegex = `(\d+(?:\.\d+)?)`
+
+var (
+	// ErrCountNotFound is returned when count regex does not match
+	// the response or the captured value is not a number.
+	ErrCountNotFound = errors.New("count not found in response")
+
+	// ErrThresholdViolated is returned when the count is out of expected
+	// bounds.
+	ErrThresholdViolated = errors.New("threshold violated")
+)
+
+// ParseCount returns the number captured by the first group of re in
+// response. The whole match is used if re has no groups.
+func ParseCount(re *regexp.Regexp, response string) (float64, error) {
+	match := re.FindStringSubmatch(response)
+	if match == nil {
+		return 0, fmt.Errorf("%w: %q does not match", ErrCountNotFound, re.String())
+	}
+
+	value := match[0]
+	if len(match) > 1 {
+		value = match[1]
+	}
+
+	count, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is not a number", ErrCountNotFound, value)
+	}
+
+	return count, nil
+}
+
+// checkCommand creates the check subcommand.
+func (executor *Executor) checkCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "check",
+		Usage:     "Execute the command and check the count parsed from the response against thresholds",
+		UsageText: "check --command players --count-regex 'online: (\\d+)' --expect-count-lt 50",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "command",
+				Usage:    "Command to send",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "count-regex",
+				Usage: "Regular expression capturing the count in the first group",
+				Value: DefaultCountRegex,
+			},
+			&cli.Float64Flag{
+				Name:  "expect-count-lt",
+				Usage: "Fail if the count is not less than the value",
+			},
+			&cli.Float64Flag{
+				Name:  "expect-count-gt",
+				Usage: "Fail if the count is not greater than the value",
+			},
+		},
+		Action: executor.check,
+	}
+}
+
+// check executes the check subcommand. The count is printed on success,
+// violated thresholds are returned as ErrThresholdViolated.
+func (executor *Executor) check(c *cli.Context) error {
+	re, err := regexp.Compile(c.String("count-regex"))
+	if err != nil {
+		return fmt.Errorf("count regex: %w", err)
+	}
+
+	ses, err := executor.NewSession(c)
+	if err != nil {
+		return err
+	}
+
+	if ses.Address == "" {
+		return ErrEmptyAddress
+	}
+
+	if ses.Password == "" && !ses.NoAuth {
+		return ErrEmptyPassword
+	}
+
+	// Escape sequences contain digits and must not be captured.
+	ses.NoColor = true
+
+	results, err := executor.Results(ses, c.String("command"))
+	if err != nil {
+		return err
+	}
+
+	count, err := ParseCount(re, results[0].Response)
+	if err != nil {
+		return err
+	}
+
+	if c.IsSet("expect-count-lt") && count >= c.Float64("expect-count-lt") {
+		return fmt.Errorf("%w: count %g is not less than %g", ErrThresholdViolated, count, c.Float64("expect-count-lt"))
+	}
+
+	if c.IsSet("expect-count-gt") && count <= c.Float64("expect-count-gt") {
+		return fmt.Errorf("%w: count %g is not greater than %g", ErrThresholdViolated, count, c.Float64("expect-count-gt"))
+	}
+
+	_, _ = fmt.Fprintf(executor.w, "OK: count %g\n", count)
+
+	return nil
+}
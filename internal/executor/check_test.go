@@ -0,0 +1,75 @@
+package executor_test
+
+import (
+	"bytes"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/gorcon/rcon"
+	"github.com/gorcon/rcon/rcontest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCount(t *testing.T) {
+	tests := []struct {
+		name     string
+		regex    string
+		response string
+		expected float64
+		err      error
+	}{
+		{"default", executor.DefaultCountRegex, "There are 12 of a max of 20 players online", 12, nil},
+		{"group", `max of (\d+)`, "There are 12 of a max of 20 players online", 20, nil},
+		{"no group", `\d+$`, "players: 7", 7, nil},
+		{"no match", `online: (\d+)`, "players: 7", 0, executor.ErrCountNotFound},
+		{"not a number", `players: (\w+)`, "players: none", 0, executor.ErrCountNotFound},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			count, err := executor.ParseCount(regexp.MustCompile(tt.regex), tt.response)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, count)
+		})
+	}
+}
+
+func TestExecutor_Check(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(func(c *rcontest.Context) {
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, "§aThere are 42 of a max of 50 players online").
+				WriteTo(c.Conn())
+		}),
+	)
+	defer serverRCON.Close()
+
+	run := func(args ...string) (string, error) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run(append([]string{os.Args[0], "-a=" + serverRCON.Addr(), "-p=password", "check", "--command=list"}, args...))
+
+		return w.String(), err
+	}
+
+	// Test the count within thresholds.
+	t.Run("ok", func(t *testing.T) {
+		output, err := run("--expect-count-lt=50", "--expect-count-gt=0")
+		assert.NoError(t, err)
+		assert.Equal(t, "OK: count 42\n", output)
+	})
+
+	// Test violated threshold returns the dedicated exit code.
+	t.Run("violated", func(t *testing.T) {
+		_, err := run("--count-regex=max of (\\d+)", "--expect-count-lt=50")
+		assert.ErrorIs(t, err, executor.ErrThresholdViolated)
+		assert.Equal(t, executor.ExitCodeCheckFailed, executor.ExitCode(err))
+	})
+}
@@ -0,0 +1,52 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/crasssr/rcon-cli/internal/colors"
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/games"
+)
+
+// CommandComplete is the prefix of the command printing known commands of
+// the game starting with the prefix in Interactive mode, e.g. ":complete ban".
+// All known commands are printed without the prefix.
+const CommandComplete = ":complete"
+
+// ErrNoGame is returned when commands are completed without game preset.
+var ErrNoGame = errors.New("no game preset: set --game to complete commands")
+
+// complete prints known commands of the game preset starting with
+// the prefix. Commands listed by the help command of the server are added
+// to them, the help is requested once per address like other commands, so
+// it passes command filters, the rate limit and events.
+func (executor *Executor) complete(w io.Writer, ses *config.Session, prefix string) error {
+	preset, ok := games.Get(ses.Game)
+	if !ok {
+		return ErrNoGame
+	}
+
+	help, ok := executor.helpCommands[ses.Address]
+	if !ok && preset.HelpCommand != "" {
+		_, raw, err := executor.requestRaw(ses, preset.HelpCommand)
+		if err != nil {
+			return fmt.Errorf("complete: %w", err)
+		}
+
+		help = preset.ParseHelp(processColorCodes(raw, colors.ModeNone))
+
+		if executor.helpCommands == nil {
+			executor.helpCommands = make(map[string][]string)
+		}
+
+		executor.helpCommands[ses.Address] = help
+	}
+
+	for _, command := range preset.Complete(prefix, help) {
+		_, _ = fmt.Fprintln(w, command)
+	}
+
+	return nil
+}
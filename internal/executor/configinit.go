@@ -0,0 +1,113 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+// ExampleConfig is the commented example config written by config-init
+// subcommand.
+const ExampleConfig = `# rcon-cli configuration file.
+# Select the environment with -e flag, e.g. ./rcon -e rust status.
+# Flags override the values of the config.
+
+# Defaults shared by all environments.
+global:
+  timeout: "10s"
+
+# Environment used when -e flag is not set.
+default:
+  # Remote server host and port.
+  address: "127.0.0.1:16260"
+  # Password can be a literal or a secret reference: env:NAME or file:/path.
+  password: "env:RCON_PASSWORD"
+  # Log file of requests and responses. Logging is disabled if empty.
+  log: "rcon-default.log"
+
+rust:
+  address: "127.0.0.1:28016"
+  password: "password"
+  # Protocol: rcon (default), telnet or web.
+  type: "web"
+
+7dtd:
+  address: "127.0.0.1:8081"
+  password: "password"
+  type: "telnet"
+  timeout: "30s"
+`
+
+// ErrConfigExists is returned when config-init would overwrite the existing
+// config file.
+var ErrConfigExists = errors.New("config file already exists: use --force to overwrite")
+
+// configInitCommand creates the config-init subcommand.
+func (executor *Executor) configInitCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "config-init",
+		Usage:     "Write the example config file to the config path",
+		UsageText: "config-init [--stdout] [--force]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "stdout",
+				Usage: "Print the example config instead of writing the file",
+			},
+			&cli.BoolFlag{
+				Name:  "force",
+				Usage: "Overwrite the existing config file",
+			},
+		},
+		Action: executor.configInit,
+	}
+}
+
+// configInit executes the config-init subcommand.
+func (executor *Executor) configInit(c *cli.Context) error {
+	if c.Bool("stdout") {
+		_, _ = fmt.Fprint(executor.w, ExampleConfig)
+
+		return nil
+	}
+
+	names := configNames(c.String("config"))
+	if len(names) != 1 || names[0] == config.StdinConfigName {
+		return ErrConfigNotEditable
+	}
+
+	name := names[0]
+	if name == "" {
+		var err error
+		if name, err = config.DefaultConfigPath(); err != nil {
+			return fmt.Errorf("config: %w", err)
+		}
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if !c.Bool("force") {
+		flags |= os.O_EXCL
+	}
+
+	const perm = 0o600
+
+	file, err := os.OpenFile(name, flags, perm)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%w: %s", ErrConfigExists, name)
+	}
+
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+	defer file.Close()
+
+	if _, err = file.WriteString(ExampleConfig); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(executor.w, "Config written to %s\n", name)
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package executor_test
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecutor_ConfigInit(t *testing.T) {
+	configFileName := t.TempDir() + "/rcon.yaml"
+
+	run := func(args ...string) (string, error) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run(append([]string{os.Args[0], "-c=" + configFileName, "config-init"}, args...))
+
+		return w.String(), err
+	}
+
+	// Test example config is valid and written once.
+	t.Run("write", func(t *testing.T) {
+		output, err := run()
+		assert.NoError(t, err)
+		assert.Equal(t, "Config written to "+configFileName+"\n", output)
+
+		cfg, err := config.NewConfig(configFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"7dtd", "default", "rust"}, cfg.Names())
+
+		_, err = run()
+		assert.ErrorIs(t, err, executor.ErrConfigExists)
+
+		_, err = run("--force")
+		assert.NoError(t, err)
+	})
+
+	t.Run("stdout", func(t *testing.T) {
+		output, err := run("--stdout")
+		assert.NoError(t, err)
+		assert.Equal(t, executor.ExampleConfig, output)
+	})
+}
@@ -0,0 +1,82 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+)
+
+// DefaultDestructiveCommands contains keywords of commands which ask for
+// confirmation before sending. The list is extended by destructive list
+// of config environment.
+var DefaultDestructiveCommands = []string{"stop", "shutdown", "ban", "deop"}
+
+// isDestructive reports whether the first word of the command is one of
+// destructive keywords. Leading slash and case are ignored.
+func isDestructive(ses *config.Session, command string) bool {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return false
+	}
+
+	keyword := strings.TrimPrefix(fields[0], "/")
+
+	for _, list := range [][]string{DefaultDestructiveCommands, ses.Destructive} {
+		for _, destructive := range list {
+			if strings.EqualFold(keyword, destructive) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isConfirmed reports whether the answer to confirmation prompt is yes.
+func isConfirmed(answer string) bool {
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
+
+// confirmDestructive asks for confirmation if any of the commands is
+// destructive. The prompt is skipped with --yes flag or if the input is
+// not a terminal.
+func (executor *Executor) confirmDestructive(ses *config.Session, commands []Command) error {
+	if ses.Yes || !isTerminal(executor.r) {
+		return nil
+	}
+
+	var destructive []string
+
+	for _, command := range commands {
+		if isDestructive(ses, command.Text) {
+			destructive = append(destructive, command.Text)
+		}
+	}
+
+	if len(destructive) == 0 {
+		return nil
+	}
+
+	promptDestructive(executor.w, strings.Join(destructive, ", "))
+
+	var answer string
+	_, _ = fmt.Fscanln(executor.r, &answer)
+
+	if !isConfirmed(answer) {
+		return ErrNotConfirmed
+	}
+
+	return nil
+}
+
+// promptDestructive prints the confirmation prompt for destructive commands.
+func promptDestructive(w io.Writer, commands string) {
+	_, _ = fmt.Fprintf(w, "Destructive command: %s. Continue? [y/N]: ", commands)
+}
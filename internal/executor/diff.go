@@ -0,0 +1,106 @@
+package executor
+
+import (
+	"io"
+	"strings"
+
+	"github.com/crasssr/rcon-cli/internal/colors"
+	"github.com/crasssr/rcon-cli/internal/config"
+)
+
+// Colors of diff lines.
+var (
+	colorAdded   = colors.Minecraft['2'] // Dark Green
+	colorRemoved = colors.Minecraft['4'] // Dark Red
+)
+
+// diffLine is a line of the line-level diff.
+type diffLine struct {
+	op   byte // ' ' unchanged, '+' added, '-' removed.
+	text string
+}
+
+// diffLines returns the line-level diff between previous and current lines
+// based on the longest common subsequence.
+func diffLines(previous []string, current []string) []diffLine {
+	// lcs[i][j] is the LCS length of previous[i:] and current[j:].
+	lcs := make([][]int, len(previous)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(current)+1)
+	}
+
+	for i := len(previous) - 1; i >= 0; i-- {
+		for j := len(current) - 1; j >= 0; j-- {
+			if previous[i] == current[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	diff := make([]diffLine, 0, len(previous)+len(current))
+
+	i, j := 0, 0
+	for i < len(previous) && j < len(current) {
+		switch {
+		case previous[i] == current[j]:
+			diff = append(diff, diffLine{op: ' ', text: previous[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			diff = append(diff, diffLine{op: '-', text: previous[i]})
+			i++
+		default:
+			diff = append(diff, diffLine{op: '+', text: current[j]})
+			j++
+		}
+	}
+
+	for ; i < len(previous); i++ {
+		diff = append(diff, diffLine{op: '-', text: previous[i]})
+	}
+
+	for ; j < len(current); j++ {
+		diff = append(diff, diffLine{op: '+', text: current[j]})
+	}
+
+	return diff
+}
+
+// printDiff prints added and removed lines between previous and current
+// responses. Added lines are green and removed lines are red in the color
+// mode of the session unless colors are disabled.
+func printDiff(w io.Writer, ses *config.Session, previous string, current string) {
+	mode := colorMode(ses)
+
+	for _, line := range diffLines(splitLines(previous), splitLines(current)) {
+		if line.op == ' ' {
+			continue
+		}
+
+		text := string(line.op) + " " + line.text
+		if mode != colors.ModeNone {
+			color := colorAdded
+			if line.op == '-' {
+				color = colorRemoved
+			}
+
+			text = color.ToANSI(mode) + text + colors.ANSIReset
+		}
+
+		_, _ = io.WriteString(w, text+"\n")
+	}
+}
+
+// splitLines splits the response into lines without the trailing newline.
+func splitLines(text string) []string {
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return nil
+	}
+
+	return strings.Split(text, "\n")
+}
@@ -0,0 +1,282 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+// ErrConfigNotEditable is returned when env subcommand is called with
+// several config files or the config from stdin.
+var ErrConfigNotEditable = errors.New("config is not editable: pass a single config file")
+
+// ErrNoEnv is returned when the config is reloaded in the session which
+// was not started from a config environment.
+var ErrNoEnv = errors.New("session is not started from config environment")
+
+// EnvInfo describes the config environment in envs subcommand output.
+type EnvInfo struct {
+	Name     string `json:"name"`
+	Address  string `json:"address"`
+	Password string `json:"password,omitempty"`
+	Type     string `json:"type"`
+	Log      string `json:"log"`
+}
+
+// Envs returns sorted environments of the config with masked passwords.
+func Envs(cfg *config.Config) []EnvInfo {
+	envs := make([]EnvInfo, 0, len(*cfg))
+
+	for _, name := range cfg.Names() {
+		ses := cfg.Env(name)
+		masked := ses.Masked()
+
+		protocol := masked.Type
+		if protocol == "" {
+			protocol = config.DefaultProtocol
+		}
+
+		envs = append(envs, EnvInfo{
+			Name: name, Address: masked.Address, Password: masked.Password, Type: protocol, Log: masked.Log,
+		})
+	}
+
+	return envs
+}
+
+// envsCommand creates the envs subcommand.
+func (executor *Executor) envsCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "envs",
+		Usage:     "List environments of the config file",
+		UsageText: "envs [--json]",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "json",
+				Usage: "Print environments as JSON array",
+			},
+		},
+		Action: executor.envs,
+	}
+}
+
+// envs executes the envs subcommand.
+func (executor *Executor) envs(c *cli.Context) error {
+	executor.configNames = configNames(c.String("config"))
+	executor.strictConfig = c.Bool("strict")
+
+	cfg, err := executor.loadConfig()
+	if err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	envs := Envs(cfg)
+
+	if c.Bool("json") {
+		printJSON(executor.w, envs)
+
+		return nil
+	}
+
+	printEnvs(executor.w, envs)
+
+	return nil
+}
+
+// envCommand creates the env subcommand editing config environments.
+func (executor *Executor) envCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "env",
+		Usage: "Rename or delete environments of the config file",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "rename",
+				Usage:     "Rename the environment",
+				UsageText: "env rename old new",
+				Action: func(c *cli.Context) error {
+					// Old and new names.
+					const names = 2
+
+					if c.NArg() != names {
+						return cli.ShowSubcommandHelp(c)
+					}
+
+					return executor.editEnv(c, c.Args().Get(0), c.Args().Get(1))
+				},
+			},
+			{
+				Name:      "delete",
+				Usage:     "Delete the environment",
+				UsageText: "env delete name",
+				Action: func(c *cli.Context) error {
+					if c.NArg() != 1 {
+						return cli.ShowSubcommandHelp(c)
+					}
+
+					return executor.editEnv(c, c.Args().Get(0), "")
+				},
+			},
+		},
+	}
+}
+
+// editEnv renames the environment to newEnv or deletes it if newEnv is
+// empty.
+func (executor *Executor) editEnv(c *cli.Context, env, newEnv string) error {
+	names := configNames(c.String("config"))
+	if len(names) != 1 || names[0] == config.StdinConfigName {
+		return ErrConfigNotEditable
+	}
+
+	name := names[0]
+	if name == "" {
+		var err error
+		if name, err = config.DefaultConfigPath(); err != nil {
+			return fmt.Errorf("config: %w", err)
+		}
+	}
+
+	if newEnv == "" {
+		if err := config.DeleteEnv(name, env); err != nil {
+			return fmt.Errorf("config: %w", err)
+		}
+
+		_, _ = fmt.Fprintf(executor.w, "Environment %s deleted\n", env)
+
+		return nil
+	}
+
+	if err := config.RenameEnv(name, env, newEnv); err != nil {
+		return fmt.Errorf("config: %w", err)
+	}
+
+	_, _ = fmt.Fprintf(executor.w, "Environment %s renamed to %s\n", env, newEnv)
+
+	return nil
+}
+
+// switchEnv returns the current connection to the pool and connects to
+// the config environment in Interactive mode. The session is kept on
+// failure.
+func (executor *Executor) switchEnv(w io.Writer, ses *config.Session, name string) error {
+	switched, env, err := executor.loadEnv(ses, name)
+	if err != nil {
+		return err
+	}
+
+	executor.releaseClient()
+
+	if err = executor.Dial(&switched); err != nil {
+		return err
+	}
+
+	*ses = switched
+	executor.env = env
+
+	protocol := ses.Type
+	if protocol == "" {
+		protocol = config.DefaultProtocol
+	}
+
+	_, _ = fmt.Fprintf(w, "Target: %s (env: %s, type: %s)\n", ses.Address, env, protocol)
+
+	return nil
+}
+
+// reloadEnv re-reads the config and updates the session from the current
+// environment in Interactive mode. The connection is reestablished only if
+// the settings it was dialed with changed.
+func (executor *Executor) reloadEnv(w io.Writer, ses *config.Session) error {
+	if executor.env == "" {
+		return ErrNoEnv
+	}
+
+	reloaded, _, err := executor.loadEnv(ses, executor.env)
+	if err != nil {
+		return err
+	}
+
+	if newPoolKey(&reloaded) != newPoolKey(ses) {
+		executor.releaseClient()
+
+		if err = executor.Dial(&reloaded); err != nil {
+			return err
+		}
+	}
+
+	*ses = reloaded
+
+	_, _ = fmt.Fprintf(w, "Config reloaded (env: %s)\n", executor.env)
+
+	return nil
+}
+
+// loadEnv reads the config and returns the copy of the session with
+// the environment applied to the session of flags like in NewSession.
+// The address, password and type of the environment replace ones of flags.
+func (executor *Executor) loadEnv(ses *config.Session, name string) (config.Session, string, error) {
+	cfg, err := executor.loadConfig()
+	if err != nil {
+		return *ses, "", fmt.Errorf("config: %w", err)
+	}
+
+	env, err := cfg.ResolveEnv(name)
+	if err != nil {
+		return *ses, "", fmt.Errorf("config: %w", err)
+	}
+
+	if _, ok := (*cfg)[env]; !ok || env == config.GlobalSection {
+		return *ses, "", fmt.Errorf("config: %w %q", config.ErrEnvNotFound, env)
+	}
+
+	// Session built without flags keeps its fields over the environment.
+	flags := sessionFlags{ses: *ses}
+	if executor.flags != nil {
+		flags = *executor.flags
+	}
+
+	loaded := *ses
+	config.CopyEnvFields(&loaded, &flags.ses)
+
+	loaded.Address = ""
+	loaded.Addresses = nil
+	loaded.Password = ""
+	loaded.Type = ""
+
+	explicit := flags.explicit
+	explicit.typeFlag = false
+	explicit.typeVar = false
+
+	if err = applyEnv(&loaded, cfg.Env(env), explicit); err != nil {
+		return *ses, "", err
+	}
+
+	if loaded.Address == "" {
+		return *ses, "", ErrEmptyAddress
+	}
+
+	if err = ValidatePatterns(&loaded); err != nil {
+		return *ses, "", err
+	}
+
+	executor.detectColor(&loaded)
+
+	return loaded, env, nil
+}
+
+// printEnvs prints environments as a table.
+func printEnvs(w io.Writer, envs []EnvInfo) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	_, _ = fmt.Fprintln(tw, "NAME\tADDRESS\tTYPE\tLOG")
+
+	for _, env := range envs {
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", env.Name, env.Address, env.Type, env.Log)
+	}
+
+	_ = tw.Flush()
+}
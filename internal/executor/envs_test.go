@@ -0,0 +1,85 @@
+package executor_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecutor_Envs(t *testing.T) {
+	configFileName := "rcon-test-envs.yaml"
+	defer os.Remove(configFileName)
+
+	run := func(t *testing.T, args ...string) string {
+		t.Helper()
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		err := app.Run(append([]string{os.Args[0], "-c=" + configFileName, "envs"}, args...))
+		assert.NoError(t, err)
+
+		return w.String()
+	}
+
+	// Test environments are sorted and passwords are masked.
+	t.Run("json", func(t *testing.T) {
+		createFile(configFileName, "rust:\n  address: 127.0.0.1:28016\n  password: secret\n  type: web\n"+
+			"default:\n  address: 127.0.0.1:16260\n  log: rcon.log\n")
+
+		var envs []executor.EnvInfo
+		err := json.Unmarshal([]byte(run(t, "--json")), &envs)
+		assert.NoError(t, err)
+		assert.Equal(t, []executor.EnvInfo{
+			{Name: "default", Address: "127.0.0.1:16260", Type: config.ProtocolRCON, Log: "rcon.log"},
+			{Name: "rust", Address: "127.0.0.1:28016", Password: config.MaskedPassword, Type: config.ProtocolWebRCON},
+		}, envs)
+	})
+
+	t.Run("table", func(t *testing.T) {
+		createFile(configFileName, "default:\n  address: 127.0.0.1:16260\n")
+
+		assert.Equal(t, "NAME     ADDRESS          TYPE  LOG\ndefault  127.0.0.1:16260  rcon  \n", run(t))
+	})
+
+	// Test empty config is printed as empty array.
+	t.Run("no environments", func(t *testing.T) {
+		createFile(configFileName, "")
+
+		assert.Equal(t, "[]\n", run(t, "--json"))
+	})
+}
+
+func TestExecutor_Env(t *testing.T) {
+	configFileName := "rcon-test-env.yaml"
+	defer os.Remove(configFileName)
+
+	createFile(configFileName, "rust:\n  address: 127.0.0.1:28016\ndefault:\n  address: 127.0.0.1:16260\n")
+
+	w := &bytes.Buffer{}
+
+	app := executor.NewExecutor(nil, w, "")
+	defer app.Close()
+
+	err := app.Run([]string{os.Args[0], "-c=" + configFileName, "env", "rename", "rust", "rust-main"})
+	assert.NoError(t, err)
+
+	err = app.Run([]string{os.Args[0], "-c=" + configFileName, "env", "delete", "default"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Environment rust renamed to rust-main\nEnvironment default deleted\n", w.String())
+
+	data, err := os.ReadFile(configFileName)
+	assert.NoError(t, err)
+	assert.Equal(t, "rust-main:\n  address: 127.0.0.1:28016\n", string(data))
+
+	// Test several config files can not be edited.
+	err = app.Run([]string{os.Args[0], "-c=" + configFileName + "," + configFileName, "env", "delete", "rust-main"})
+	assert.ErrorIs(t, err, executor.ErrConfigNotEditable)
+}
@@ -0,0 +1,51 @@
+package executor
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Connection lifecycle event types.
+const (
+	EventDialStart     = "dial_start"
+	EventDialOK        = "dial_ok"
+	EventAuthOK        = "auth_ok"
+	EventCommandSent   = "command_sent"
+	EventCommandResult = "command_result"
+	EventDisconnect    = "disconnect"
+)
+
+// Event is a connection lifecycle event written as a NDJSON line.
+type Event struct {
+	Event    string    `json:"event"`
+	Time     time.Time `json:"time"`
+	Address  string    `json:"address,omitempty"`
+	Type     string    `json:"type,omitempty"`
+	Command  string    `json:"command,omitempty"`
+	Response string    `json:"response,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// SetEvents sets the writer for lifecycle events. Events are not written
+// if w is nil.
+func (executor *Executor) SetEvents(w io.Writer) {
+	executor.events = w
+}
+
+// emit writes the lifecycle event if events are enabled.
+func (executor *Executor) emit(event Event) {
+	if executor.events == nil {
+		return
+	}
+
+	event.Time = time.Now().UTC()
+
+	js, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	_, _ = fmt.Fprintln(executor.events, string(js))
+}
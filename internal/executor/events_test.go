@@ -0,0 +1,88 @@
+package executor_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/gorcon/rcon"
+	"github.com/gorcon/rcon/rcontest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecutor_SetEvents(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(func(c *rcontest.Context) {
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, "Can I help you?").WriteTo(c.Conn())
+		}),
+	)
+	defer serverRCON.Close()
+
+	t.Run("lifecycle", func(t *testing.T) {
+		w := bytes.Buffer{}
+		events := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		app.SetEvents(&events)
+
+		err := app.Execute(&w, &config.Session{Address: serverRCON.Addr(), Password: "password"}, "help")
+		assert.NoError(t, err)
+		assert.NoError(t, app.Close())
+
+		got := decodeEvents(t, events.Bytes())
+		assert.Equal(t, []string{
+			executor.EventDialStart, executor.EventDialOK, executor.EventAuthOK,
+			executor.EventCommandSent, executor.EventCommandResult, executor.EventDisconnect,
+		}, eventTypes(got))
+		assert.Equal(t, "Can I help you?", got[4].Response)
+		assert.False(t, got[0].Time.IsZero())
+	})
+
+	t.Run("auth failed", func(t *testing.T) {
+		w := bytes.Buffer{}
+		events := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		app.SetEvents(&events)
+
+		err := app.Execute(&w, &config.Session{Address: serverRCON.Addr(), Password: "wrong"}, "help")
+		assert.Error(t, err)
+
+		got := decodeEvents(t, events.Bytes())
+		assert.Equal(t, []string{executor.EventDialStart, executor.EventDisconnect}, eventTypes(got))
+		assert.Equal(t, "auth: rcon: authentication failed", got[1].Error)
+	})
+}
+
+// decodeEvents checks that every line of events output is a JSON object
+// and returns decoded events.
+func decodeEvents(t *testing.T, output []byte) []executor.Event {
+	t.Helper()
+
+	var events []executor.Event
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		var event executor.Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("not JSON line %q: %v", scanner.Text(), err)
+		}
+
+		events = append(events, event)
+	}
+
+	return events
+}
+
+func eventTypes(events []executor.Event) []string {
+	types := make([]string, 0, len(events))
+	for _, event := range events {
+		types = append(types, event.Event)
+	}
+
+	return types
+}
@@ -1,7 +1,7 @@
 package executor
 
 import (
-	"bufio"
+	"bytes"
 	"errors"
 	"flag"
 	"fmt"
@@ -9,18 +9,60 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"text/template"
+	"time"
 
-	"github.com/gorcon/rcon"
+	"github.com/crasssr/rcon-cli/internal/colors"
 	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/games"
 	"github.com/crasssr/rcon-cli/internal/logger"
+	"github.com/gorcon/rcon"
 	"github.com/gorcon/telnet"
 	"github.com/gorcon/websocket"
 	"github.com/urfave/cli/v2"
+	"golang.org/x/crypto/ssh"
 )
 
 // CommandQuit is the command for exit from Interactive mode.
 const CommandQuit = ":q"
 
+// CommandEnv is the prefix of the command switching the config environment
+// in Interactive mode, e.g. ":env prod".
+const CommandEnv = ":env"
+
+// CommandReload is the command re-reading the config file in Interactive
+// mode.
+const CommandReload = ":reload"
+
+// CommandSave is the prefix of the command writing successfully executed
+// commands of Interactive mode to the command file, e.g. ":save session.rcon".
+const CommandSave = ":save"
+
+// CommandContinuation is the trailing character that continues the command
+// on the next line in Interactive mode.
+const CommandContinuation = `\`
+
+// Environment variables with connection details used when the flags are
+// not set. The names are prefixed with --env-prefix.
+const (
+	EnvAddress  = "RCON_ADDRESS"
+	EnvPassword = "RCON_PASSWORD"
+	EnvType     = "RCON_TYPE"
+)
+
+// Sources of the session address printed with --variables.
+const (
+	addressSourceFlag   = "flag"
+	addressSourceEnv    = "environment variable"
+	addressSourceFile   = "address file"
+	addressSourceConfig = "config"
+)
+
+// DefaultUsage is the app usage shown in help unless it is set with
+// SetUsage.
+const DefaultUsage = "CLI for executing queries on a remote server"
+
 // CommandsResponseSeparator is symbols that is written between responses of
 // several commands if more than one command was called.
 const CommandsResponseSeparator = "--------"
@@ -37,6 +79,33 @@ var (
 
 	// ErrCommandEmpty is returned when executed command length equal 0.
 	ErrCommandEmpty = errors.New("command is not set")
+
+	// ErrHaltTimeout is returned when the whole run took longer than
+	// the halt timeout.
+	ErrHaltTimeout = errors.New("halt timeout exceeded")
+
+	// ErrStdinConsumed is returned when commands are not passed in args
+	// but stdin is used to read the config.
+	ErrStdinConsumed = errors.New("commands must be passed in args when config is read from stdin")
+
+	// ErrNoAuthUnsupported is returned when --no-auth flag is set for
+	// a protocol which mandates authentication.
+	ErrNoAuthUnsupported = errors.New("connection without authentication is not supported")
+)
+
+// Exit codes.
+const (
+	// ExitCodeError is returned on any error without a dedicated exit code.
+	ExitCodeError = 1
+
+	// ExitCodeCheckFailed is returned when the check subcommand threshold
+	// is violated. The value matches the critical state of monitoring
+	// plugins.
+	ExitCodeCheckFailed = 2
+
+	// ExitCodeHaltTimeout is returned when the halt timeout exceeded.
+	// The value matches the timeout utility exit code.
+	ExitCodeHaltTimeout = 124
 )
 
 // ExecuteCloser is the interface that groups Execute and Close methods.
@@ -48,81 +117,127 @@ type ExecuteCloser interface {
 // Executor is a cli commands execute wrapper.
 type Executor struct {
 	version string
+	name    string
+	usage   string
 	r       io.Reader
 	w       io.Writer
+	stderr  io.Writer
 	app     *cli.App
 
-	client ExecuteCloser
-}
-
-// Apply or remove color codes text
-func processColorCodes(text string, stripColors bool) string {
-    // Map of Minecraft color codes to ANSI escape codes.
-    colorMap := map[rune]string{
-        '0': "\033[30m", // Black
-        '1': "\033[34m", // Dark Blue
-        '2': "\033[32m", // Dark Green
-        '3': "\033[36m", // Dark Aqua
-        '4': "\033[31m", // Dark Red
-        '5': "\033[35m", // Dark Purple
-        '6': "\033[33m", // Gold
-        '7': "\033[37m", // Gray
-        '8': "\033[90m", // Dark Gray
-        '9': "\033[94m", // Blue
-        'a': "\033[92m", // Green
-        'b': "\033[96m", // Aqua
-        'c': "\033[91m", // Red
-        'd': "\033[95m", // Light Purple
-        'e': "\033[93m", // Yellow
-        'f': "\033[97m", // White
-        'r': "\033[0m",  // Reset
-        // Add more as needed
-    }
-	
-	if stripColors {
-        // Remove color codes by stripping § and following character.
-        return strings.Map(func(r rune) rune {
-            if r == '§' {
-                return -1
-            }
-            return r
-        }, text)
-    } else {
-        // Apply ANSI color codes.
-        var result strings.Builder
-        skip := false
-
-        for i, r := range text {
-            if skip {
-                skip = false
-                continue
-            }
-            if r == '§' && i+1 < len(text) {
-                color, ok := colorMap[rune(text[i+1])]
-                if ok {
-                    result.WriteString(color)
-                    skip = true
-                    continue
-                }
-            }
-            result.WriteRune(r)
-        }
-
-        // Ensure reset at the end.
-        result.WriteString("\033[0m")
-        return result.String()
-    }
+	// mu guards writes of client, which is used by the goroutine running
+	// commands and read by Close.
+	mu        sync.Mutex
+	client    ExecuteCloser
+	clientKey poolKey
+	pooled    bool
+	halt      <-chan struct{}
+	pool      *pool
+	limiter   *rateLimiter
+	events    io.Writer
+	cache     map[string]cacheEntry
+
+	// helpCommands contains commands parsed from the help output of
+	// the server per address.
+	helpCommands map[string][]string
+
+	logTemplate   *template.Template
+	logWriter     *logger.Writer
+	configNames   []string
+	strictConfig  bool
+	env           string
+	flags         *sessionFlags
+	addressSource string
+	retries       int
+
+	sshClient *ssh.Client
+	sshTarget string
+}
+
+// processColorCodes applies or removes Minecraft color codes in text. The
+// codes are converted to ANSI sequences of the color mode and removed in
+// none mode. The text is processed by runes, so multibyte characters before
+// a code do not shift the code character.
+func processColorCodes(text string, mode string) string {
+	var result strings.Builder
+
+	stripColors := mode == colors.ModeNone
+	colored := false
+	runes := []rune(text)
+
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == colors.Marker {
+			if i+1 < len(runes) {
+				if color, ok := colors.Minecraft[runes[i+1]]; ok {
+					if !stripColors {
+						result.WriteString(color.ToANSI(mode))
+						colored = true
+					}
+
+					i++
+
+					continue
+				}
+			}
+
+			// Remove markers of unknown codes too.
+			if stripColors {
+				continue
+			}
+		}
+
+		result.WriteRune(runes[i])
+	}
+
+	// Ensure reset at the end if any color was applied.
+	if colored {
+		result.WriteString(colors.ANSIReset)
+	}
+
+	return result.String()
+}
+
+// ExitCode returns the process exit code for the error returned by Run.
+func ExitCode(err error) int {
+	if errors.Is(err, ErrHaltTimeout) {
+		return ExitCodeHaltTimeout
+	}
+
+	if errors.Is(err, ErrThresholdViolated) {
+		return ExitCodeCheckFailed
+	}
+
+	return ExitCodeError
 }
 
 // NewExecutor creates a new Executor.
 func NewExecutor(r io.Reader, w io.Writer, version string) *Executor {
 	return &Executor{
 		version: version,
+		name:    filepath.Base(os.Args[0]),
+		usage:   DefaultUsage,
 		r:       r,
 		w:       w,
+		stderr:  os.Stderr,
 	}
 }
 
+// SetName sets the program name shown in help and usage examples. It is
+// used by tools embedding the executor under a different name.
+func (executor *Executor) SetName(name string) {
+	executor.name = name
+}
+
+// SetUsage sets the app usage shown in help.
+func (executor *Executor) SetUsage(usage string) {
+	executor.usage = usage
+}
+
+// SetStderr sets the writer for responses routed to stderr and for
+// lifecycle events.
+func (executor *Executor) SetStderr(w io.Writer) {
+	executor.stderr = w
+}
+
 // Run is the entry point to the cli app.
 func (executor *Executor) Run(arguments []string) error {
 	executor.init()
@@ -138,79 +253,422 @@ func (executor *Executor) Run(arguments []string) error {
 // a remote server. If the address and password flags were received the
 // configuration file is ignored.
 func (executor *Executor) NewSession(c *cli.Context) (*config.Session, error) {
+	// Config names are kept to switch environments in Interactive mode.
+	executor.configNames = configNames(c.String("config"))
+	executor.strictConfig = c.Bool("strict")
+
 	ses := config.Session{
-		Address:    c.String("address"),
-		Password:   c.String("password"),
-		Type:       c.String("type"),
-		Log:        c.String("log"),
-		SkipErrors: c.Bool("skip"),
-		Timeout:    c.Duration("timeout"),
-		Variables:  c.Bool("variables"),
+		Address:              c.String("address"),
+		Password:             c.String("password"),
+		Proxy:                c.String("proxy"),
+		SSH:                  c.String("ssh"),
+		SSHKey:               c.String("ssh-key"),
+		SSHKnownHosts:        c.String("ssh-known-hosts"),
+		TLSCA:                c.String("tls-ca"),
+		TLSInsecure:          c.Bool("tls-insecure"),
+		Type:                 c.String("type"),
+		Game:                 c.String("game"),
+		OutputFormat:         c.String("output-format"),
+		Log:                  c.String("log"),
+		SkipErrors:           c.Bool("skip"),
+		Timeout:              c.Duration("timeout"),
+		HaltTimeout:          c.Duration("halt-timeout"),
+		WarnSlow:             c.Duration("warn-slow"),
+		Repeat:               c.Duration("repeat"),
+		RepeatCount:          c.Int("repeat-count"),
+		OnChange:             c.Bool("on-change"),
+		Diff:                 c.Bool("diff"),
+		NoColor:              c.Bool("no-color"),
+		ForceColor:           c.Bool("force-color"),
+		ColorMode:            c.String("color-mode"),
+		ResponseTo:           c.String("response-to"),
+		NativeTelnet:         c.Bool("native-telnet"),
+		Rate:                 c.Float64("rate"),
+		TrimPrefix:           c.String("trim-prefix"),
+		TrimSuffix:           c.String("trim-suffix"),
+		PerLine:              c.Bool("per-line"),
+		DenyPatterns:         c.StringSlice("deny-pattern"),
+		AllowOnly:            c.StringSlice("allow-only"),
+		NoAuth:               c.Bool("no-auth"),
+		NoWait:               c.Bool("no-wait"),
+		MultiPacket:          c.Bool("multi-packet"),
+		ReconnectOnEmpty:     c.Bool("reconnect-on-empty"),
+		TimeoutRetries:       c.Int("command-timeout-retries"),
+		RetryBudget:          c.Int("retry-budget"),
+		LineEnding:           c.String("line-ending"),
+		LastOnly:             c.Bool("last-only"),
+		Labels:               c.Bool("labels"),
+		Nonce:                c.String("nonce"),
+		PrintSent:            c.Bool("print-sent"),
+		Hex:                  c.Bool("hex"),
+		Progress:             c.Bool("progress") && !c.Bool("quiet"),
+		MergeResponses:       c.Bool("merge-responses"),
+		Chaining:             c.Bool("chaining"),
+		StripEcho:            c.Bool("strip-echo"),
+		JSONPath:             c.String("jsonpath"),
+		StripEchoIgnoreCase:  c.Bool("strip-echo-ignore-case"),
+		Pager:                c.Bool("pager"),
+		OutputFile:           c.String("output-file"),
+		OutputAppend:         c.Bool("output-append"),
+		StripLeadingSlash:    c.Bool("strip-leading-slash"),
+		Yes:                  c.Bool("yes"),
+		LogOnly:              c.Bool("log-only"),
+		LogFormat:            c.String("log-format"),
+		LogTemplate:          c.String("log-template"),
+		LogFlushInterval:     c.Duration("log-flush-interval"),
+		LogSync:              c.Bool("log-sync"),
+		NoLogMkdir:           !c.Bool("log-mkdir"),
+		CacheTTL:             c.Duration("cache-ttl"),
+		Flatten:              c.Bool("flatten"),
+		FlattenSeparator:     c.String("flatten-separator"),
+		NoBanner:             !c.Bool("banner"),
+		Transcript:           c.String("transcript"),
+		InteractiveKeepalive: c.Duration("interactive-keepalive"),
+		KeepaliveCommand:     c.String("keepalive-command"),
+		Variables:            c.Bool("variables"),
+	}
+
+	executor.addressSource = ""
+	if ses.Address != "" {
+		executor.addressSource = addressSourceFlag
+	}
+
+	lookupEnvVars(&ses, c.String("env-prefix"), !c.IsSet("type"))
+
+	if executor.addressSource == "" && ses.Address != "" {
+		executor.addressSource = addressSourceEnv
 	}
 
+	// Game preset type applies only if the type is not set explicitly.
+	explicit := explicitFlags{
+		timeout:   c.IsSet("timeout"),
+		logFormat: c.IsSet("log-format"),
+		typeFlag:  c.IsSet("type"),
+		typeVar:   c.IsSet("type") || os.Getenv(c.String("env-prefix")+EnvType) != "",
+	}
+
+	if name := c.String("address-file"); name != "" {
+		var err error
+		if ses.Addresses, err = ReadAddressFile(name); err != nil {
+			return &ses, err
+		}
+
+		// Address from flag is tried first.
+		if ses.Address != "" {
+			ses.Addresses = append([]string{ses.Address}, ses.Addresses...)
+		}
+
+		ses.Address = ses.Addresses[0]
+
+		if executor.addressSource == "" {
+			executor.addressSource = addressSourceFile
+		}
+	}
+
+	// The config environment switched in Interactive mode is applied to
+	// the session of flags too.
+	executor.flags = &sessionFlags{ses: ses, explicit: explicit}
+
 	if ses.Address != "" && ses.Password != "" {
+		applyGamePreset(&ses, explicit.typeVar)
+		defaultTimeout(&ses)
+
 		return &ses, nil
 	}
 
-	cfg, err := config.NewConfig(c.String("config"))
+	cfg, err := executor.loadConfig()
+	if err != nil {
+		return &ses, fmt.Errorf("config: %w", err)
+	}
+
+	env, err := cfg.ResolveEnv(c.String("env"))
 	if err != nil {
 		return &ses, fmt.Errorf("config: %w", err)
 	}
 
-	env := c.String("env")
 	if env == "" {
 		env = config.DefaultConfigEnv
 	}
 
-	// Get variables from config environment if flags are not defined.
+	executor.env = env
+
+	if err = applyEnv(&ses, cfg.Env(env), explicit); err != nil {
+		return &ses, err
+	}
+
+	if executor.addressSource == "" && ses.Address != "" {
+		executor.addressSource = addressSourceConfig
+	}
+
+	return &ses, nil
+}
+
+// explicitFlags reports which session fields with default values are set
+// explicitly, so they take precedence over the config environment.
+type explicitFlags struct {
+	timeout   bool
+	logFormat bool
+	// typeFlag is set by --type flag, typeVar by the flag or environment
+	// variable. Type set by environment variable is replaced by config
+	// but not by the game preset.
+	typeFlag bool
+	typeVar  bool
+}
+
+// sessionFlags contains the session built from flags and environment
+// variables before the config environment is applied.
+type sessionFlags struct {
+	ses      config.Session
+	explicit explicitFlags
+}
+
+// applyEnv fills the session fields missing in flags from the config
+// environment and applies the game preset and the protocol default timeout.
+func applyEnv(ses *config.Session, envSes config.Env, explicit explicitFlags) error {
+	if len(ses.Addresses) == 0 {
+		ses.Addresses = envSes.Addresses
+	}
+
 	if ses.Address == "" {
-		ses.Address = (*cfg)[env].Address
+		ses.Address = envSes.Address
+	}
+
+	if ses.Address == "" && len(ses.Addresses) > 0 {
+		ses.Address = ses.Addresses[0]
 	}
 
 	if ses.Password == "" {
-		ses.Password = (*cfg)[env].Password
+		var err error
+		if ses.Password, err = config.ResolveSecret(envSes.Password); err != nil {
+			return fmt.Errorf("config: password: %w", err)
+		}
 	}
 
 	if ses.Log == "" {
-		ses.Log = (*cfg)[env].Log
+		ses.Log = envSes.Log
 	}
 
-	if ses.Type == "" {
-		ses.Type = (*cfg)[env].Type
+	if ses.LogTemplate == "" {
+		ses.LogTemplate = envSes.LogTemplate
 	}
 
-	return &ses, nil
+	if ses.LogFlushInterval == 0 {
+		ses.LogFlushInterval = time.Duration(envSes.LogFlushInterval)
+	}
+
+	if !ses.LogSync {
+		ses.LogSync = envSes.LogSync
+	}
+
+	if ses.Proxy == "" {
+		ses.Proxy = envSes.Proxy
+	}
+
+	if ses.SSH == "" {
+		ses.SSH = envSes.SSH
+	}
+
+	if ses.SSHKey == "" {
+		ses.SSHKey = envSes.SSHKey
+	}
+
+	if ses.SSHKnownHosts == "" {
+		ses.SSHKnownHosts = envSes.SSHKnownHosts
+	}
+
+	if ses.TLSCA == "" {
+		ses.TLSCA = envSes.TLSCA
+	}
+
+	if ses.Game == "" {
+		ses.Game = envSes.Game
+	}
+
+	if len(ses.DenyPatterns) == 0 {
+		ses.DenyPatterns = envSes.DenyPatterns
+	}
+
+	if len(ses.AllowOnly) == 0 {
+		ses.AllowOnly = envSes.AllowOnly
+	}
+
+	if ses.CacheTTL == 0 {
+		ses.CacheTTL = time.Duration(envSes.CacheTTL)
+	}
+
+	// Timeout, type and log format flags have default values so only
+	// explicit flags override the environment.
+	if !explicit.timeout && envSes.Timeout != 0 {
+		ses.Timeout = time.Duration(envSes.Timeout)
+	}
+
+	if !explicit.typeFlag && envSes.Type != "" {
+		ses.Type = envSes.Type
+	}
+
+	if !explicit.logFormat && envSes.LogFormat != "" {
+		ses.LogFormat = envSes.LogFormat
+	}
+
+	if ses.ColorMode == "" {
+		ses.ColorMode = envSes.ColorMode
+	}
+
+	ses.NoColor = ses.NoColor || envSes.NoColor
+	ses.ForceColor = ses.ForceColor || envSes.ForceColor
+
+	ses.Cacheable = envSes.Cacheable
+	ses.Destructive = envSes.Destructive
+
+	applyGamePreset(ses, explicit.typeVar || envSes.Type != "")
+	defaultTimeout(ses)
+
+	return nil
+}
+
+// defaultTimeout sets the default timeout of the session protocol if
+// the timeout is not set by flag or config.
+func defaultTimeout(ses *config.Session) {
+	if ses.Timeout <= 0 {
+		ses.Timeout = config.DefaultProtocolTimeout(ses.Type)
+	}
+}
+
+// lookupEnvVars fills the address, password and type missing in flags from
+// environment variables with the prefix, e.g. MYGAME_RCON_PASSWORD. Type
+// is taken only if the flag is not set explicitly.
+func lookupEnvVars(ses *config.Session, prefix string, typeUnset bool) {
+	if ses.Address == "" {
+		ses.Address = os.Getenv(prefix + EnvAddress)
+	}
+
+	if ses.Password == "" {
+		ses.Password = os.Getenv(prefix + EnvPassword)
+	}
+
+	if value := os.Getenv(prefix + EnvType); typeUnset && value != "" {
+		ses.Type = value
+	}
 }
 
 // Dial sends auth request for remote server. Returns en error if
 // address or password is incorrect.
 func (executor *Executor) Dial(ses *config.Session) error {
-	var err error
-
-	if executor.client == nil {
-		switch ses.Type {
-		case config.ProtocolTELNET:
-			executor.client, err = telnet.Dial(ses.Address, ses.Password, telnet.SetDialTimeout(ses.Timeout))
-		case config.ProtocolWebRCON:
-			executor.client, err = websocket.Dial(
-				ses.Address, ses.Password, websocket.SetDialTimeout(ses.Timeout), websocket.SetDeadline(ses.Timeout))
-		default:
-			executor.client, err = rcon.Dial(
-				ses.Address, ses.Password, rcon.SetDialTimeout(ses.Timeout), rcon.SetDeadline(ses.Timeout))
+	if executor.checkout(ses) {
+		return nil
+	}
+
+	if executor.halted() {
+		return ErrHaltTimeout
+	}
+
+	// Only WebRCON passes the password in the URL and can connect without
+	// it. RCON and TELNET libraries always send the auth request.
+	if ses.NoAuth && ses.Type != config.ProtocolWebRCON {
+		protocol := ses.Type
+		if protocol == "" {
+			protocol = config.DefaultProtocol
+		}
+
+		return fmt.Errorf("auth: %w by %s protocol", ErrNoAuthUnsupported, protocol)
+	}
+
+	if len(ses.Addresses) > 0 {
+		return executor.dialFailover(ses)
+	}
+
+	return executor.dial(ses)
+}
+
+// dial connects and authenticates to the session address.
+func (executor *Executor) dial(ses *config.Session) error {
+	executor.emit(Event{Event: EventDialStart, Address: ses.Address, Type: ses.Type})
+
+	// Type can be entered in Interactive mode after the session is created.
+	defaultTimeout(ses)
+
+	// Proxy and SSH tunnels forward only TCP connections.
+	if ses.Type == config.ProtocolHLDS && tunneled(ses) {
+		err := fmt.Errorf("auth: %w", ErrUDPTunnel)
+
+		executor.emit(Event{Event: EventDisconnect, Address: ses.Address, Type: ses.Type, Error: err.Error()})
+
+		return err
+	}
+
+	address, err := executor.dialAddress(ses)
+	if err != nil {
+		err = fmt.Errorf("auth: %w", err)
+
+		executor.emit(Event{Event: EventDisconnect, Address: ses.Address, Type: ses.Type, Error: err.Error()})
+
+		return err
+	}
+
+	var client ExecuteCloser
+
+	// The deadline is the timeout of each request, clients refresh it
+	// before every read and write, so a long batch over one connection
+	// doesn't expire.
+	switch ses.Type {
+	case config.ProtocolTELNET, config.ProtocolTELNETS:
+		client, err = telnet.Dial(address, ses.Password, telnet.SetDialTimeout(ses.Timeout))
+	case config.ProtocolWebRCON:
+		// TODO: Reuse session tokens issued by WebRCON gateways. The websocket
+		// library sends the password in the URL path and does not expose the
+		// handshake response, so there is no token to cache yet.
+		password := ses.Password
+		if ses.NoAuth {
+			password = ""
+		}
+
+		client, err = websocket.Dial(
+			address, password, websocket.SetDialTimeout(ses.Timeout), websocket.SetDeadline(ses.Timeout))
+	case config.ProtocolHLDS:
+		client, err = dialHLDS(address, ses.Password, ses.Timeout, ses.Timeout)
+	default:
+		if ses.MultiPacket {
+			client, err = dialMultiPacket(address, ses.Password, ses.Timeout, ses.Timeout)
+
+			break
 		}
+
+		client, err = rcon.Dial(
+			address, ses.Password, rcon.SetDialTimeout(ses.Timeout), rcon.SetDeadline(ses.Timeout))
 	}
 
 	if err != nil {
-		executor.client = nil
+		// The server behind the proxy, SSH or TLS can't be probed directly.
+		if !tunneled(ses) && ses.Type != config.ProtocolTELNETS && !executor.halted() {
+			err = withProtocolHint(ses, err)
+		}
+
+		err = fmt.Errorf("auth: %w", err)
+
+		executor.emit(Event{Event: EventDisconnect, Address: ses.Address, Type: ses.Type, Error: err.Error()})
 
-		return fmt.Errorf("auth: %w", err)
+		return err
 	}
 
+	executor.setClient(client, newPoolKey(ses))
+
+	// Clients connect and authenticate in a single call, so both events
+	// are emitted once the call succeeds.
+	executor.emit(Event{Event: EventDialOK, Address: ses.Address, Type: ses.Type})
+	executor.emit(Event{Event: EventAuthOK, Address: ses.Address, Type: ses.Type})
+
 	return nil
 }
 
 // Execute sends commands to Execute to the remote server and prints the response.
 func (executor *Executor) Execute(w io.Writer, ses *config.Session, commands ...string) error {
+	return executor.ExecuteCommands(w, ses, NewCommands(commands...)...)
+}
+
+// ExecuteCommands sends commands to Execute to the remote server, collects
+// their results and prints them. Responses of commands with output
+// redirection are written to the files.
+func (executor *Executor) ExecuteCommands(w io.Writer, ses *config.Session, commands ...Command) error {
 	if len(commands) == 0 {
 		return ErrCommandEmpty
 	}
@@ -219,84 +677,247 @@ func (executor *Executor) Execute(w io.Writer, ses *config.Session, commands ...
 	if ses.Type == config.ProtocolWebRCON {
 		defer func() {
 			if executor.client != nil {
-				_ = executor.client.Close()
-				executor.client = nil
+				executor.dropClient()
+
+				executor.emit(Event{Event: EventDisconnect, Address: ses.Address, Type: ses.Type})
 			}
 		}()
 	}
 
 	if err := executor.Dial(ses); err != nil {
+		if ses.OutputFormat == OutputFormatJSON {
+			printJSONError(w, err)
+		}
+
+		// Unreachable server is skipped like a failed command so the run
+		// continues with the next target.
+		if ses.SkipErrors {
+			if ses.OutputFormat != OutputFormatJSON {
+				_, _ = fmt.Fprintln(w, fmt.Errorf("execute: %w", err))
+			}
+
+			return nil
+		}
+
 		return fmt.Errorf("execute: %w", err)
 	}
 
-	for i, command := range commands {
-		if err := executor.execute(w, ses, command); err != nil {
-			return err
-		}
+	if ses.MergeResponses {
+		return executor.executeMerged(w, ses, commands)
+	}
 
-		if i+1 != len(commands) {
-			_, _ = fmt.Fprintln(w, CommandsResponseSeparator)
-		}
+	results, err := executor.results(ses, commands)
+	if printErr := executor.printResults(w, ses, commands, results); printErr != nil {
+		return printErr
 	}
 
-	return nil
+	return err
 }
 
 // Interactive reads stdin, parses commands, executes them on remote server
 // and prints the responses.
 func (executor *Executor) Interactive(r io.Reader, w io.Writer, ses *config.Session) error {
-	if ses.Address == "" {
-		_, _ = fmt.Fprint(w, "Enter remote host and port [ip:port]: ")
-		_, _ = fmt.Fscanln(r, &ses.Address)
-	}
-
-	if ses.Password == "" {
-		_, _ = fmt.Fprint(w, "Enter password: ")
-		_, _ = fmt.Fscanln(r, &ses.Password)
+	// Prompts are printed to stderr in JSON output format, so stdout
+	// contains only JSON responses.
+	prompt := w
+	if ses.OutputFormat == OutputFormatJSON {
+		prompt = executor.stderr
 	}
 
-	if ses.Type == "" {
-		_, _ = fmt.Fprint(w, "Enter protocol type (empty for rcon): ")
-		_, _ = fmt.Fscanln(r, &ses.Type)
+	if err := promptSession(r, prompt, ses); err != nil {
+		return err
 	}
 
 	switch ses.Type {
-	case config.ProtocolTELNET:
-		return telnet.DialInteractive(r, w, ses.Address, ses.Password)
-	case "", config.ProtocolRCON, config.ProtocolWebRCON:
+	case config.ProtocolTELNET, config.ProtocolTELNETS:
+		if ses.NativeTelnet {
+			address, err := executor.dialAddress(ses)
+			if err != nil {
+				return fmt.Errorf("auth: %w", err)
+			}
+
+			return telnet.DialInteractive(r, w, address, ses.Password)
+		}
+
+		fallthrough
+	case "", config.ProtocolRCON, config.ProtocolWebRCON, config.ProtocolHLDS:
 		if err := executor.Dial(ses); err != nil {
 			return err
 		}
 
-		_, _ = fmt.Fprintf(w, "Waiting commands for %s (or type %s to exit)\n> ", ses.Address, CommandQuit)
+		if !ses.NoBanner {
+			_, _ = fmt.Fprintf(prompt, "Waiting commands for %s (or type %s to exit)\n", ses.Address, CommandQuit)
+		}
+
+		_, _ = fmt.Fprint(prompt, "> ")
+
+		var record *transcript
+		if ses.Transcript != "" {
+			record = newTranscript(ses.Address)
+
+			defer func() {
+				if err := record.Save(ses.Transcript); err != nil {
+					_, _ = fmt.Fprintln(prompt, err)
+				}
+			}()
+		}
+
+		paging := usePager(w, ses.Pager)
+
+		var (
+			pending strings.Builder
+			history []string
+		)
+
+		lines := readLines(r)
+		for {
+			line, ok := executor.nextLine(ses, lines)
+			if !ok {
+				break
+			}
+
+			// Line ending with continuation character is joined with the
+			// next one like in shell.
+			if strings.HasSuffix(line, CommandContinuation) {
+				pending.WriteString(strings.TrimSuffix(line, CommandContinuation))
+				_, _ = fmt.Fprint(prompt, "... ")
+
+				continue
+			}
+
+			command := pending.String() + line
+			pending.Reset()
 
-		scanner := bufio.NewScanner(r)
-		for scanner.Scan() {
-			command := scanner.Text()
 			if command != "" {
 				if command == CommandQuit {
 					break
 				}
 
-				if err := executor.Execute(w, ses, command); err != nil {
+				if command == CommandReload {
+					if err := executor.reloadEnv(prompt, ses); err != nil {
+						_, _ = fmt.Fprintln(prompt, err)
+					}
+
+					_, _ = fmt.Fprint(prompt, "> ")
+
+					continue
+				}
+
+				if name, ok := strings.CutPrefix(command, CommandSave+" "); ok {
+					if err := WriteCommandFile(strings.TrimSpace(name), history); err != nil {
+						_, _ = fmt.Fprintln(prompt, err)
+					} else {
+						_, _ = fmt.Fprintf(prompt, "Saved %d commands to %s\n", len(history), strings.TrimSpace(name))
+					}
+
+					_, _ = fmt.Fprint(prompt, "> ")
+
+					continue
+				}
+
+				if prefix, ok := strings.CutPrefix(command+" ", CommandComplete+" "); ok {
+					if err := executor.complete(prompt, ses, strings.TrimSpace(prefix)); err != nil {
+						_, _ = fmt.Fprintln(prompt, err)
+					}
+
+					_, _ = fmt.Fprint(prompt, "> ")
+
+					continue
+				}
+
+				if env, ok := strings.CutPrefix(command, CommandEnv+" "); ok {
+					if err := executor.switchEnv(prompt, ses, strings.TrimSpace(env)); err != nil {
+						_, _ = fmt.Fprintln(prompt, err)
+					}
+
+					_, _ = fmt.Fprint(prompt, "> ")
+
+					continue
+				}
+
+				if !ses.Yes && isDestructive(ses, command) {
+					// Nobody answers the prompt from piped input and the
+					// next line is a command, so the command is refused.
+					if !isTerminal(r) {
+						_, _ = fmt.Fprintf(prompt, "%s: %s: use --yes to send destructive commands from "+
+							"non-terminal input\n> ", ErrNotConfirmed, command)
+
+						continue
+					}
+
+					promptDestructive(prompt, command)
+
+					if answer, _ := executor.nextLine(ses, lines); !isConfirmed(answer) {
+						_, _ = fmt.Fprint(prompt, ErrNotConfirmed, "\n> ")
+
+						continue
+					}
+				}
+
+				// Paged response is buffered and printed after execution.
+				var paged bytes.Buffer
+
+				out := w
+				if paging {
+					out = &paged
+				}
+
+				cw := out
+				if record != nil {
+					cw = record.Writer(out, command)
+				}
+
+				err := executor.Execute(cw, ses, command)
+				// The error of JSON output format is printed in the response.
+				if err != nil && ses.SkipErrors && ses.OutputFormat != OutputFormatJSON {
+					_, _ = fmt.Fprintln(cw, err)
+				}
+
+				if err == nil {
+					history = append(history, command)
+				}
+
+				if paging {
+					page(w, &paged)
+				}
+
+				if err != nil && !ses.SkipErrors {
 					return err
 				}
 			}
 
-			_, _ = fmt.Fprint(w, "> ")
+			_, _ = fmt.Fprint(prompt, "> ")
 		}
 	default:
-		_, _ = fmt.Fprintf(w, "Unsupported protocol type (%q). Allowed %q, %q and %q protocols\n",
-			ses.Type, config.ProtocolRCON, config.ProtocolWebRCON, config.ProtocolTELNET)
+		_, _ = fmt.Fprintf(w, "Unsupported protocol type (%q). Allowed %q, %q, %q, %q and %q protocols\n",
+			ses.Type, config.ProtocolRCON, config.ProtocolWebRCON, config.ProtocolTELNET, config.ProtocolTELNETS,
+			config.ProtocolHLDS)
 	}
 
 	return nil
 }
 
-// Close closes connection to remote server.
+// Close closes connection to remote server. It is safe to call Close more
+// than once.
 func (executor *Executor) Close() error {
-	if executor.client != nil {
-		return executor.client.Close()
+	// Clients are tunneled through SSH connection, so it is closed last.
+	defer executor.closeSSH()
+	defer executor.closeLogWriter()
+
+	if executor.pool != nil {
+		executor.pool.Close()
+		executor.pool = nil
+	}
+
+	executor.mu.Lock()
+	client := executor.client
+	executor.client = nil
+	executor.mu.Unlock()
+
+	if client != nil {
+		executor.emit(Event{Event: EventDisconnect})
+
+		return client.Close()
 	}
 
 	return nil
@@ -305,17 +926,25 @@ func (executor *Executor) Close() error {
 // init creates a new cli Application.
 func (executor *Executor) init() {
 	app := cli.NewApp()
-	app.Usage = "CLI for executing queries on a remote server"
+	app.Name = executor.name
+	app.Usage = executor.usage
 	app.Description = "Can be run in two modes - in the mode of a single query and in terminal mode of reading the " +
 		"input stream. \n\n" + "To run single mode type commands after options flags. Example: \n" +
-		filepath.Base(os.Args[0]) + " -a 127.0.0.1:16260 -p password command1 command2 \n\n" +
+		executor.name + " -a 127.0.0.1:16260 -p password command1 command2 \n\n" +
 		"To run terminal mode just do not specify commands to execute. Example: \n" +
-		filepath.Base(os.Args[0]) + " -a 127.0.0.1:16260 -p password"
+		executor.name + " -a 127.0.0.1:16260 -p password"
 	app.Version = executor.version
+	app.Writer = executor.w
 	app.Copyright = "Copyright (c) 2022 Pavel Korotkiy (outdead)"
 	app.HideHelpCommand = true
 	app.Flags = executor.getFlags()
 	app.Action = executor.action
+	// TODO: Expose Prometheus metrics with --metrics-addr flag when daemon
+	// subcommand is added. There is no long running mode to scrape yet.
+	app.Commands = []*cli.Command{
+		executor.benchCommand(), executor.watchCommand(), executor.envsCommand(), executor.envCommand(),
+		executor.checkCommand(), executor.configInitCommand(), executor.serveHealthCommand(),
+	}
 
 	executor.app = app
 }
@@ -328,6 +957,10 @@ func (executor *Executor) getFlags() []cli.Flag {
 			Aliases: []string{"a"},
 			Usage:   "Set host and port to remote server. Example 127.0.0.1:16260",
 		},
+		&cli.StringFlag{
+			Name:  "address-file",
+			Usage: "Path to the file with host:port lines. Addresses are tried in order until one authenticates",
+		},
 		&cli.StringFlag{
 			Name:    "password",
 			Aliases: []string{"p"},
@@ -336,7 +969,7 @@ func (executor *Executor) getFlags() []cli.Flag {
 		&cli.StringFlag{
 			Name:    "type",
 			Aliases: []string{"t"},
-			Usage:   "Specify type of connection",
+			Usage:   "Specify type of connection: rcon, telnet, telnets, web or hlds",
 			Value:   config.DefaultProtocol,
 		},
 		&cli.StringFlag{
@@ -345,27 +978,62 @@ func (executor *Executor) getFlags() []cli.Flag {
 			Usage:   "Path to the log file. If not specified it is taken from the config",
 		},
 		&cli.StringFlag{
-			Name:    "config",
-			Aliases: []string{"c"},
-			Usage:   "Path to the configuration file",
-			Value:   config.DefaultConfigName,
+			Name:  "log-format",
+			Usage: "Set log file format: text, html or json. HTML keeps response colors, JSON encodes binary responses in base64",
+			Value: logger.FormatText,
+		},
+		&cli.BoolFlag{
+			Name:  "log-mkdir",
+			Usage: "Create missing directories of the log file, use --log-mkdir=false to fail instead",
+			Value: true,
 		},
 		&cli.StringFlag{
-			Name:    "env",
-			Aliases: []string{"e"},
-			Usage:   "Config environment with server credentials",
-			Value:   config.DefaultConfigEnv,
+			Name:  "log-template",
+			Usage: "Set Go template of log line with .Timestamp, .Address, .Type, .Command and .Response fields",
 		},
-		&cli.BoolFlag{
+		&cli.DurationFlag{
+			Name:  "log-flush-interval",
+			Usage: "Keep the log file open and flush buffered records on the interval and on exit. Zero opens the file for every record",
+		},
+		&cli.BoolFlag{
+			Name:  "log-sync",
+			Usage: "Write and sync every log record to disk at once for audit logs",
+		},
+		&cli.BoolFlag{
+			Name:  "log-only",
+			Usage: "Write responses to the log file without printing them",
+		},
+		&cli.StringFlag{
+			Name:    "config",
+			Aliases: []string{"c"},
+			Usage:   "Path to the configuration file. Several comma-separated files are merged in order, - reads it from stdin",
+			Value:   config.DefaultConfigName,
+		},
+		&cli.BoolFlag{
+			Name:  "strict",
+			Usage: "Return an error on unknown fields of config environments instead of ignoring them",
+		},
+		&cli.StringFlag{
+			Name:    "env",
+			Aliases: []string{"e"},
+			Usage:   "Config environment with server credentials",
+			Value:   config.DefaultConfigEnv,
+		},
+		&cli.StringFlag{
+			Name:  "env-prefix",
+			Usage: "Prefix of RCON_ADDRESS, RCON_PASSWORD and RCON_TYPE environment variables, e.g. MYGAME_",
+		},
+		&cli.BoolFlag{
 			Name:    "skip",
 			Aliases: []string{"s"},
-			Usage:   "Skip errors and run next command",
+			Usage:   "Skip dial and command errors and run next command",
 		},
 		&cli.DurationFlag{
 			Name:    "timeout",
 			Aliases: []string{"T"},
 			Usage:   "Set dial and execute timeout",
-			Value:   config.DefaultTimeout,
+			// Zero timeout is replaced by the default of the protocol.
+			DefaultText: "10s for rcon and telnet, 20s for web",
 		},
 		&cli.BoolFlag{
 			Name:    "variables",
@@ -373,6 +1041,294 @@ func (executor *Executor) getFlags() []cli.Flag {
 			Usage:   "Print stored variables and exit",
 			Value:   false,
 		},
+		&cli.BoolFlag{
+			Name:  "print-config",
+			Usage: "Print the effective configuration with masked password and exit",
+		},
+		&cli.BoolFlag{
+			Name:  "connect-only",
+			Usage: "Connect and authenticate without executing commands, print OK on success and exit",
+		},
+		&cli.BoolFlag{
+			Name:    "quiet",
+			Aliases: []string{"q"},
+			Usage:   "Do not print OK on success of --connect-only and --progress lines",
+		},
+		&cli.StringFlag{
+			Name:    "command-file",
+			Aliases: []string{"f"},
+			Usage:   "Path or glob of the files with commands to execute, one per line",
+		},
+		&cli.StringFlag{
+			Name:  "command-delimiter",
+			Usage: "Split each command argument by the delimiter into several commands, e.g. ';;'. Not split by default",
+		},
+		&cli.StringFlag{
+			Name:  "command-fifo",
+			Usage: "Path to the named pipe to execute commands written to it on the persistent connection until interrupted",
+		},
+		&cli.BoolFlag{
+			Name:  "interactive-from-file",
+			Usage: "Enter interactive mode on the same connection after executing the commands",
+		},
+		&cli.BoolFlag{
+			Name:  "allow-empty-glob",
+			Usage: "Do not fail when the command file glob matches no files",
+		},
+		&cli.StringFlag{
+			Name:  "section",
+			Usage: "Run only commands of the [section] of the command file",
+		},
+		&cli.StringFlag{
+			Name:  "input-format",
+			Usage: "Format of the command list: text or json. JSON list is read from command file or stdin",
+			Value: InputFormatText,
+		},
+		&cli.StringSliceFlag{
+			Name:  "var",
+			Usage: "Substitute {key} placeholders in commands with the value in key=value format. Can be repeated",
+		},
+		&cli.BoolFlag{
+			Name:  "allow-unset-vars",
+			Usage: "Leave placeholders without --var values as is instead of failing",
+		},
+		&cli.StringFlag{
+			Name:    "game",
+			Aliases: []string{"g"},
+			Usage:   "Apply game preset of protocol, default port, response processing and command completion. Supported games: " + strings.Join(games.Names(), ", "),
+		},
+		&cli.StringFlag{
+			Name:    "output-format",
+			Aliases: []string{"o"},
+			Usage:   "Set responses output format: text or json",
+			Value:   OutputFormatText,
+		},
+		&cli.StringFlag{
+			Name:  "response-to",
+			Usage: "Write responses to stdout or stderr",
+			Value: ResponseToStdout,
+		},
+		&cli.BoolFlag{
+			Name:  "native-telnet",
+			Usage: "Use the built-in loop of the telnet library in terminal mode without logging and color processing",
+		},
+		&cli.BoolFlag{
+			Name:  "confirm-target",
+			Usage: "Print the resolved target and ask for confirmation in terminal before executing",
+		},
+		&cli.BoolFlag{
+			Name:  "no-auth",
+			Usage: "Connect without password. Supported only by web protocol",
+		},
+		&cli.StringFlag{
+			Name:  "proxy",
+			Usage: "Route the connection through SOCKS5 or HTTP proxy, e.g. socks5://127.0.0.1:1080",
+		},
+		&cli.StringFlag{
+			Name:  "ssh",
+			Usage: "Tunnel the connection through SSH server, e.g. admin@bastion:22",
+		},
+		&cli.StringFlag{
+			Name:  "ssh-key",
+			Usage: "Path to the private key for SSH authentication. SSH agent is used if not set",
+		},
+		&cli.StringFlag{
+			Name:  "ssh-known-hosts",
+			Usage: "Path to the known hosts file verifying SSH server key. Default is ~/.ssh/known_hosts",
+		},
+		&cli.StringFlag{
+			Name:  "tls-ca",
+			Usage: "Path to the PEM certificates verifying telnets server. Default is system roots",
+		},
+		&cli.BoolFlag{
+			Name:  "tls-insecure",
+			Usage: "Skip verification of telnets server certificate",
+		},
+		&cli.BoolFlag{
+			Name:  "multi-packet",
+			Usage: "Collect rcon responses split into several packets, e.g. for cvarlist",
+		},
+		&cli.BoolFlag{
+			Name:  "no-wait",
+			Usage: "Treat connection closed or timed out after sending the command as success, e.g. for shutdown",
+		},
+		&cli.BoolFlag{
+			Name:    "yes",
+			Aliases: []string{"no-confirm"},
+			Usage:   "Send destructive commands like stop or ban without confirmation",
+		},
+		&cli.BoolFlag{
+			Name:  "strip-leading-slash",
+			Usage: "Remove leading slash from commands before sending. Enabled by minecraft game",
+		},
+		&cli.StringFlag{
+			Name:    "output-file",
+			Aliases: []string{"O"},
+			Usage:   "Copy the printed output to the file. With --log-only the output is written only to the file",
+		},
+		&cli.BoolFlag{
+			Name:  "output-append",
+			Usage: "Append to the output file instead of truncating it",
+		},
+		&cli.BoolFlag{
+			Name:  "pager",
+			Usage: "Pipe responses longer than the terminal through $PAGER in interactive mode",
+		},
+		&cli.StringFlag{
+			Name:  "jsonpath",
+			Usage: "Print only values of the JSON response matching the path, e.g. .players[*].name",
+		},
+		&cli.BoolFlag{
+			Name:  "strip-echo",
+			Usage: "Remove the first response line if it repeats the sent command",
+		},
+		&cli.BoolFlag{
+			Name:  "strip-echo-ignore-case",
+			Usage: "Ignore case when matching the echoed command of --strip-echo",
+		},
+		&cli.BoolFlag{
+			Name:  "labels",
+			Usage: "Print each command as a header above its response instead of separators",
+		},
+		&cli.BoolFlag{
+			Name:  "chaining",
+			Usage: "Split commands by && and ; operators: && runs the next command only if the previous one succeeded",
+		},
+		&cli.BoolFlag{
+			Name:  "merge-responses",
+			Usage: "Join responses of all commands with a newline and print them as one response without separators",
+		},
+		&cli.BoolFlag{
+			Name:  "progress",
+			Usage: "Print running: <command> line to stderr before each command, suppressed with --quiet",
+		},
+		&cli.StringFlag{
+			Name:  "nonce",
+			Usage: "Append comment with unique value after the comment marker to every command, e.g. '//', to bypass response caches",
+		},
+		&cli.BoolFlag{
+			Name:  "print-sent",
+			Usage: "Print the exact command payload sent to the server to stderr after slash stripping and line ending",
+		},
+		&cli.BoolFlag{
+			Name:  "hex",
+			Usage: "Print the --print-sent payload as hex bytes",
+		},
+		&cli.BoolFlag{
+			Name:  "last-only",
+			Usage: "Execute all commands but print only the response of the last one",
+		},
+		&cli.StringFlag{
+			Name:  "line-ending",
+			Usage: "Terminate commands with lf, crlf or cr before sending. TELNET commands are always terminated with crlf",
+		},
+		&cli.BoolFlag{
+			Name:  "reconnect-on-empty",
+			Usage: "Reconnect and retry once when the command returns an empty response",
+		},
+		&cli.IntFlag{
+			Name:  "command-timeout-retries",
+			Usage: "Re-send the command up to the number of times when it times out. Connection errors are not retried",
+		},
+		&cli.IntFlag{
+			Name:  "retry-budget",
+			Usage: "Cap the total number of command retries of the whole run, failures propagate at once after it is spent",
+		},
+		&cli.BoolFlag{
+			Name:  "events",
+			Usage: "Write connection lifecycle events to stderr as NDJSON",
+		},
+		&cli.BoolFlag{
+			Name:  "flatten",
+			Usage: "Join lines of multi-line responses into a single line",
+		},
+		&cli.StringFlag{
+			Name:  "flatten-separator",
+			Usage: "Separator used by flatten instead of new lines",
+			Value: DefaultFlattenSeparator,
+		},
+		&cli.StringFlag{
+			Name:  "transcript",
+			Usage: "Write transcript of interactive session with timestamps to the file on exit",
+		},
+		&cli.BoolFlag{
+			Name:  "banner",
+			Usage: "Print the banner with the target address in interactive mode, use --banner=false to hide it",
+			Value: true,
+		},
+		&cli.DurationFlag{
+			Name:  "interactive-keepalive",
+			Usage: "Send keepalive command in interactive mode after the idle period",
+		},
+		&cli.StringFlag{
+			Name:  "keepalive-command",
+			Usage: "Command sent by interactive keepalive, its response is suppressed",
+			Value: DefaultKeepaliveCommand,
+		},
+		&cli.DurationFlag{
+			Name:  "halt-timeout",
+			Usage: "Set the maximum duration of the whole run including dial and all commands",
+		},
+		&cli.DurationFlag{
+			Name:  "warn-slow",
+			Usage: "Print a warning to stderr when a command takes longer than the duration",
+		},
+		&cli.Float64Flag{
+			Name:  "rate",
+			Usage: "Limit the number of commands sent per second. Unlimited by default",
+		},
+		&cli.StringFlag{
+			Name:  "trim-prefix",
+			Usage: "Remove the prefix from the response, e.g. [Server]",
+		},
+		&cli.StringFlag{
+			Name:  "trim-suffix",
+			Usage: "Remove the suffix from the response",
+		},
+		&cli.BoolFlag{
+			Name:  "per-line",
+			Usage: "Apply --trim-prefix and --trim-suffix to every line of the response",
+		},
+		&cli.StringSliceFlag{
+			Name:  "deny-pattern",
+			Usage: "Refuse to send commands matching the regular expression. Can be repeated",
+		},
+		&cli.StringSliceFlag{
+			Name:  "allow-only",
+			Usage: "Send only commands matching the regular expression. Can be repeated",
+		},
+		&cli.DurationFlag{
+			Name:  "cache-ttl",
+			Usage: "Cache responses of commands listed in config cacheable list for the duration",
+		},
+		&cli.DurationFlag{
+			Name:  "repeat",
+			Usage: "Repeat commands with the interval until interrupted",
+		},
+		&cli.IntFlag{
+			Name:  "repeat-count",
+			Usage: "Stop repeating commands after the number of iterations",
+		},
+		&cli.BoolFlag{
+			Name:  "on-change",
+			Usage: "Print repeated command response only when it changes",
+		},
+		&cli.BoolFlag{
+			Name:  "diff",
+			Usage: "Print added and removed lines of repeated command response when it changes",
+		},
+		&cli.BoolFlag{
+			Name:  "no-color",
+			Usage: "Disable colors in responses and diffs",
+		},
+		&cli.BoolFlag{
+			Name:  "force-color",
+			Usage: "Keep colors when output is redirected to a file or pipe",
+		},
+		&cli.StringFlag{
+			Name:  "color-mode",
+			Usage: "Color palette of responses: 16, 256, truecolor or none. Detected from the terminal by default",
+		},
 	}
 }
 
@@ -389,8 +1345,94 @@ func (executor *Executor) action(c *cli.Context) error {
 		return nil
 	}
 
-	commands := c.Args().Slice()
+	if c.Bool("print-config") {
+		return executor.printConfig(ses)
+	}
+
+	if c.Bool("events") {
+		executor.SetEvents(executor.stderr)
+	}
+
+	if err = ValidateGame(ses.Game); err != nil {
+		return err
+	}
+
+	if err = ValidateOutputFormat(ses.OutputFormat); err != nil {
+		return err
+	}
+
+	if err = ValidatePatterns(ses); err != nil {
+		return err
+	}
+
+	if err = ValidateResponseTo(ses.ResponseTo); err != nil {
+		return err
+	}
+
+	if err = logger.ValidateFormat(ses.LogFormat); err != nil {
+		return err
+	}
+
+	if ses.LogTemplate != "" {
+		if executor.logTemplate, err = logger.ParseTemplate(ses.LogTemplate); err != nil {
+			return err
+		}
+	}
+
+	if err = ValidateLineEnding(ses); err != nil {
+		return err
+	}
+
+	if err = ValidateColorMode(ses.ColorMode); err != nil {
+		return err
+	}
+
+	if err = ValidateJSONPath(ses); err != nil {
+		return err
+	}
+
+	if err = ValidateProxy(ses); err != nil {
+		return err
+	}
+
+	if err = ValidateSSH(ses); err != nil {
+		return err
+	}
+
+	executor.detectColor(ses)
+
+	if c.Bool("connect-only") {
+		return executor.connectOnly(ses, c.Bool("quiet"))
+	}
+
+	if name := c.String("command-fifo"); name != "" {
+		return executor.commandFIFO(c, ses, name)
+	}
+
+	commands, err := executor.getCommands(c)
+	if err != nil {
+		return err
+	}
+
+	if ses.OutputFile != "" {
+		restore, err := executor.teeOutput(ses)
+		if err != nil {
+			return err
+		}
+		defer restore()
+	}
+
+	// Empty glob is allowed and there is nothing to execute.
+	if len(commands) == 0 && c.String("command-file") != "" {
+		return nil
+	}
+
 	if len(commands) == 0 {
+		// Stdin is already consumed by the config.
+		if configFromStdin(c) {
+			return ErrStdinConsumed
+		}
+
 		return executor.Interactive(executor.r, executor.w, ses)
 	}
 
@@ -398,53 +1440,396 @@ func (executor *Executor) action(c *cli.Context) error {
 		return ErrEmptyAddress
 	}
 
-	if ses.Password == "" {
+	if ses.Password == "" && !ses.NoAuth {
 		return ErrEmptyPassword
 	}
 
-	return executor.Execute(executor.w, ses, commands...)
+	if c.Bool("confirm-target") {
+		if err = executor.confirmTarget(ses, c.String("env")); err != nil {
+			return err
+		}
+	}
+
+	if err = executor.confirmDestructive(ses, commands); err != nil {
+		return err
+	}
+
+	if err = executor.executeWithHalt(ses, commands); err != nil || !c.Bool("interactive-from-file") {
+		return err
+	}
+
+	// Commands primed the state, the open connection is reused for
+	// exploring it manually.
+	if configFromStdin(c) {
+		return ErrStdinConsumed
+	}
+
+	return executor.Interactive(executor.r, executor.w, ses)
 }
 
-// execute sends command to Execute to the remote server and prints the response.
-func (executor *Executor) execute(w io.Writer, ses *config.Session, command string) error {
-	if command == "" {
-		return ErrCommandEmpty
+// connectOnly dials the remote server to validate the address and
+// credentials and prints OK unless quiet.
+func (executor *Executor) connectOnly(ses *config.Session, quiet bool) error {
+	if ses.Address == "" {
+		return ErrEmptyAddress
 	}
 
-	var result string
-	var err error
+	if ses.Password == "" && !ses.NoAuth {
+		return ErrEmptyPassword
+	}
 
-	result, err = executor.client.Execute(command)
-	if result != "" {
-		result = strings.TrimSpace(result)
+	if err := executor.Dial(ses); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+
+	if !quiet {
+		_, _ = fmt.Fprintln(executor.w, "OK")
+	}
+
+	return nil
+}
+
+// executeWithHalt executes commands and stops the run when the halt timeout
+// exceeded. The connection of the request in flight is closed, retries and
+// dials stop on the halt signal and the run is waited for. The timeout of
+// the dial in flight is capped by the halt timeout.
+func (executor *Executor) executeWithHalt(ses *config.Session, commands []Command) error {
+	if ses.HaltTimeout <= 0 {
+		return executor.run(ses, commands)
+	}
+
+	limited := *ses
+	defaultTimeout(&limited)
+
+	if limited.Timeout > limited.HaltTimeout {
+		limited.Timeout = limited.HaltTimeout
+	}
+
+	halt := make(chan struct{})
+	executor.halt = halt
+
+	defer func() { executor.halt = nil }()
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- executor.run(&limited, commands)
+	}()
+
+	timer := time.NewTimer(ses.HaltTimeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-done:
+		// The request deadline may expire together with the halt timeout.
+		if err != nil && !timer.Stop() {
+			return fmt.Errorf("%w: %s", ErrHaltTimeout, ses.HaltTimeout)
+		}
+
+		return err
+	case <-timer.C:
+		close(halt)
+		executor.interrupt()
+		<-done
+
+		// The interrupted connection is closed already.
+		executor.dropClient()
+
+		return fmt.Errorf("%w: %s", ErrHaltTimeout, ses.HaltTimeout)
+	}
+}
+
+// halted returns true if the halt timeout exceeded.
+func (executor *Executor) halted() bool {
+	select {
+	case <-executor.halt:
+		return true
+	default:
+		return false
+	}
+}
+
+// interrupt closes the connection of the request in flight, so it returns
+// without waiting for the timeout. The connection is dropped once the run
+// returned.
+func (executor *Executor) interrupt() {
+	executor.mu.Lock()
+	client := executor.client
+	executor.mu.Unlock()
+
+	if client != nil {
+		_ = client.Close()
+	}
+}
+
+// run executes commands once or repeats them if repeat interval is set.
+func (executor *Executor) run(ses *config.Session, commands []Command) error {
+	w := executor.responseWriter(ses)
+
+	if ses.Repeat > 0 {
+		return executor.Repeat(w, ses, commands...)
+	}
+
+	return executor.ExecuteCommands(w, ses, commands...)
+}
+
+// configNames splits comma-separated list of config file names.
+func configNames(value string) []string {
+	names := strings.Split(value, ",")
+	for i := range names {
+		names[i] = strings.TrimSpace(names[i])
+	}
+
+	return names
+}
+
+// loadConfig reads the config files set with --config flag. Unknown fields
+// are errors in strict mode.
+func (executor *Executor) loadConfig() (*config.Config, error) {
+	if executor.strictConfig {
+		return config.NewStrictConfigFrom(executor.r, executor.configNames...)
+	}
 
-		// Minecraft code here
-		stripColors := false // Set this based on your needs or configuration
-		result = processColorCodes(result, stripColors)
-		
-		_, _ = fmt.Fprintln(w, result)
+	return config.NewConfigFrom(executor.r, executor.configNames...)
+}
+
+// configFromStdin reports whether the config is read from stdin.
+func configFromStdin(c *cli.Context) bool {
+	for _, name := range configNames(c.String("config")) {
+		if name == config.StdinConfigName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getCommands returns commands from the command file followed by commands
+// from args. Placeholders in commands are substituted with --var variables.
+func (executor *Executor) getCommands(c *cli.Context) ([]Command, error) {
+	var commands []Command
+
+	format := c.String("input-format")
+	if err := ValidateInputFormat(format); err != nil {
+		return nil, err
 	}
 
+	switch name := c.String("command-file"); {
+	case name != "":
+		var err error
+		if commands, err = ReadCommandFiles(name, format, c.Bool("allow-empty-glob")); err != nil {
+			return nil, err
+		}
+
+		if c.IsSet("section") {
+			if commands, err = FilterSection(commands, c.String("section")); err != nil {
+				return nil, err
+			}
+		}
+	case format == InputFormatJSON && c.Args().Len() == 0 && !configFromStdin(c):
+		// JSON command list is read from stdin instead of interactive mode.
+		var err error
+		if commands, err = ReadJSONCommands(executor.r); err != nil {
+			return nil, err
+		}
+	}
+
+	commands = append(commands, NewCommands(SplitCommands(c.Args().Slice(), c.String("command-delimiter"))...)...)
+
+	vars, err := ParseVars(c.StringSlice("var"))
 	if err != nil {
-		if ses.SkipErrors {
-			_, _ = fmt.Fprintln(w, fmt.Errorf("execute: %w", err))
-		} else {
-			return fmt.Errorf("execute: %w", err)
+		return nil, err
+	}
+
+	return ApplyVars(commands, vars, len(vars) == 0 || c.Bool("allow-unset-vars"))
+}
+
+// request sends command to Execute to the remote server and returns
+// the processed response.
+func (executor *Executor) request(ses *config.Session, command string) (string, error) {
+	result, _, err := executor.requestRaw(ses, command)
+
+	return result, err
+}
+
+// requestRaw sends command to Execute to the remote server and returns
+// the processed response and the raw response as it was received.
+func (executor *Executor) requestRaw(ses *config.Session, command string) (string, string, error) {
+	if err := checkCommand(ses, command); err != nil {
+		return "", "", err
+	}
+
+	if entry, ok := executor.cached(ses, command); ok {
+		return entry.response, entry.raw, nil
+	}
+
+	executor.throttle(ses)
+
+	// The connection could be dropped by the previous command.
+	if err := executor.Dial(ses); err != nil {
+		return "", "", err
+	}
+
+	executor.emit(Event{Event: EventCommandSent, Command: command})
+
+	// Servers drop idle connections, so the pooled one may be closed.
+	pooled := executor.pooled
+	executor.pooled = false
+
+	payload := terminate(ses, withNonce(ses, command))
+	executor.printSent(ses, payload)
+
+	result, err := executor.client.Execute(payload)
+	if pooled && isDropped(err) {
+		result, err = executor.resend(ses, command)
+	}
+
+	if ses.TimeoutRetries > 0 {
+		result, err = executor.retryOnTimeout(ses, command, result, err)
+	}
+
+	if ses.ReconnectOnEmpty && result == "" && err == nil && executor.spendRetry(ses) {
+		result, err = executor.resend(ses, command)
+	}
+
+	if ses.NoWait && isClosedAfterSend(err) {
+		executor.dropClient()
+
+		result, err = "", nil
+	}
+
+	if err != nil {
+		executor.dropUncorrelated(err)
+	}
+
+	defer func() {
+		event := Event{Event: EventCommandResult, Command: command, Response: result}
+		if err != nil {
+			event.Error = err.Error()
+		}
+
+		executor.emit(event)
+	}()
+
+	raw := result
+
+	if result != "" {
+		result = strings.TrimSpace(result)
+		result = stripEcho(ses, command, result)
+
+		// Game error patterns match the plain text, so they are checked
+		// before Minecraft color codes are rendered to ANSI sequences.
+		plain := trimResponse(ses, processColorCodes(result, colors.ModeNone))
+		if processErr := processResponse(ses.Game, plain); processErr != nil && err == nil {
+			err = processErr
+		}
+
+		result = processColorCodes(result, colorMode(ses))
+		result = trimResponse(ses, result)
+
+		// Invalid JSON response is printed as is with the error.
+		if extracted, jsonErr := extractJSONPath(ses, result); jsonErr != nil && err == nil {
+			err = jsonErr
+		} else if jsonErr == nil {
+			result = extracted
 		}
 	}
 
-	if err = logger.Write(ses.Log, ses.Address, command, result); err != nil {
-		_, _ = fmt.Fprintln(w, fmt.Errorf("log: %w", err))
+	if err == nil {
+		executor.store(ses, command, result, raw)
 	}
 
-	return nil
+	return result, raw, err
 }
 
+// printSeparator prints separator between responses of several commands.
+// There are no separators in JSON output format and in log only mode.
+func printSeparator(w io.Writer, ses *config.Session) {
+	if ses.OutputFormat != OutputFormatJSON && !ses.LogOnly {
+		_, _ = fmt.Fprintln(w, CommandsResponseSeparator)
+	}
+}
+
+// printLabel prints the command as a header above its response. Labels
+// replace separators and are not printed in JSON output format and in log
+// only mode.
+func printLabel(w io.Writer, ses *config.Session, command string) {
+	if ses.Labels && ses.OutputFormat != OutputFormatJSON && !ses.LogOnly {
+		_, _ = fmt.Fprintf(w, "=== %s ===\n", command)
+	}
+}
+
+// printProgress prints the command to stderr before it is sent if progress
+// is enabled. Stdout is kept clean for the responses.
+func (executor *Executor) printProgress(ses *config.Session, command string) {
+	if ses.Progress {
+		_, _ = fmt.Fprintf(executor.stderr, "running: %s\n", command)
+	}
+}
+
+// printSent prints the command payload to stderr after all transformations
+// if it is enabled. TELNET library appends the line ending itself.
+func (executor *Executor) printSent(ses *config.Session, payload string) {
+	if !ses.PrintSent {
+		return
+	}
+
+	if ses.Hex {
+		_, _ = fmt.Fprintf(executor.stderr, "sent: % x\n", payload)
+
+		return
+	}
+
+	_, _ = fmt.Fprintf(executor.stderr, "sent: %q\n", payload)
+}
+
+// printVariables prints the session with masked password and the runtime
+// details of its resolution.
 func (executor *Executor) printVariables(ses *config.Session, c *cli.Context) {
 	_, _ = fmt.Fprint(executor.w, "Got Print Variables param.\n")
-	_ = ses.Print(executor.w)
+
+	masked := ses.Masked()
+	_ = masked.Print(executor.w)
+
+	protocol := ses.Type
+	if protocol == "" {
+		protocol = config.DefaultProtocol
+	}
+
+	source := executor.addressSource
+	if source == "" {
+		source = "not set"
+	}
 
 	_, _ = fmt.Fprint(executor.w, "\nPrint other variables:\n")
 	_, _ = fmt.Fprintf(executor.w, "Path to config file (if used): %s\n", c.String("config"))
+
+	for _, name := range executor.configNames {
+		_, _ = fmt.Fprintf(executor.w, "Config file %s\n", configFileStatus(name))
+	}
+
 	_, _ = fmt.Fprintf(executor.w, "Cofig environment: %s\n", c.String("env"))
+	_, _ = fmt.Fprintf(executor.w, "Effective protocol: %s\n", protocol)
+	_, _ = fmt.Fprintf(executor.w, "Address source: %s\n", source)
+}
+
+// configFileStatus returns the config file path and whether it is found.
+// Empty name is resolved to the default config path.
+func configFileStatus(name string) string {
+	if name == config.StdinConfigName {
+		return "stdin"
+	}
+
+	if name == "" {
+		var err error
+		if name, err = config.DefaultConfigPath(); err != nil {
+			return err.Error()
+		}
+	}
+
+	if _, err := os.Stat(name); err != nil {
+		return name + ": not found"
+	}
+
+	return name + ": found"
 }
@@ -4,17 +4,22 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
-	"github.com/gorcon/rcon"
+	"github.com/crasssr/rcon-cli/internal/colors"
 	"github.com/crasssr/rcon-cli/internal/config"
 	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/crasssr/rcon-cli/internal/logger"
+	"github.com/gorcon/rcon"
 	"github.com/gorcon/rcon/rcontest"
 	"github.com/gorcon/telnet"
 	"github.com/gorcon/telnet/telnettest"
@@ -26,11 +31,36 @@ import (
 const ConfigLayoutJSON = `{"%s": {"address": "%s", "password": "%s", "log": "%s", "type": "%s"}}`
 const ConfigLayoutYAML = "%s:\n  address: %s\n  password: %s\n  log: %s\n  type: %s"
 
+// syncBuffer is a buffer safe for concurrent writes and reads.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.buf.String()
+}
+
 func handlersRCON(c *rcontest.Context) {
 	switch c.Request().Body() {
 	case "help":
 		responseBody := "Can I help you?"
 		rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, responseBody).WriteTo(c.Conn())
+	case "sleep":
+		time.Sleep(time.Second)
+		rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, "awake").WriteTo(c.Conn())
+	case "shutdown":
+		// Server goes down without response.
 	default:
 		rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, "unknown command").WriteTo(c.Conn())
 	}
@@ -175,6 +205,344 @@ func TestExecute(t *testing.T) {
 		assert.Error(t, err)
 	})
 
+	// Test protocol mismatch hint.
+	t.Run("protocol mismatch", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		err := app.Execute(&w, &config.Session{Address: serverTELNET.Addr(), Password: "password", Type: config.ProtocolWebRCON}, "help")
+		assert.ErrorIs(t, err, executor.ErrProtocolMismatch)
+		assert.Contains(t, err.Error(), "try --type telnet")
+	})
+
+	// Test UDP servers and password errors are not probed for protocol.
+	t.Run("protocol mismatch skipped", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		err := app.Execute(&w, &config.Session{Address: serverTELNET.Addr(), Password: "password", Type: config.ProtocolHLDS}, "help")
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, executor.ErrProtocolMismatch)
+
+		err = app.Execute(&w, &config.Session{Address: serverTELNET.Addr(), Password: "wrong", Type: config.ProtocolTELNET}, "help")
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, executor.ErrProtocolMismatch)
+	})
+
+	// Test rate limit spaces commands.
+	t.Run("rate limit", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		start := time.Now()
+
+		err := app.Execute(&w, &config.Session{Address: serverRCON.Addr(), Password: "password", Rate: 20}, "help", "help", "help")
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	})
+
+	// Test fire-and-forget command without response from server.
+	t.Run("no wait", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", Timeout: 100 * time.Millisecond}
+
+		err := app.Execute(&w, ses, "shutdown")
+		assert.Error(t, err)
+
+		app = executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses.NoWait = true
+
+		err = app.Execute(&w, ses, "shutdown", "help")
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "Can I help you?")
+	})
+
+	// Test retry of the command returned empty response over a new connection.
+	t.Run("reconnect on empty", func(t *testing.T) {
+		var requests int32
+
+		serverFlaky := rcontest.NewServer(
+			rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+			rcontest.SetCommandHandler(func(c *rcontest.Context) {
+				responseBody := ""
+				if atomic.AddInt32(&requests, 1) > 1 {
+					responseBody = "online"
+				}
+
+				rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, responseBody).WriteTo(c.Conn())
+			}),
+		)
+		defer serverFlaky.Close()
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverFlaky.Addr(), Password: "password", ReconnectOnEmpty: true}
+
+		err := app.Execute(&w, ses, "status")
+		assert.NoError(t, err)
+		assert.Equal(t, "online\n", w.String())
+		assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	})
+
+	// Test retry of the timed out command.
+	t.Run("command timeout retries", func(t *testing.T) {
+		var requests int32
+
+		serverSlow := rcontest.NewServer(
+			rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+			rcontest.SetCommandHandler(func(c *rcontest.Context) {
+				if atomic.AddInt32(&requests, 1) == 1 {
+					time.Sleep(300 * time.Millisecond)
+				}
+
+				rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, "online").WriteTo(c.Conn())
+			}),
+		)
+		defer serverSlow.Close()
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverSlow.Addr(), Password: "password", Timeout: 100 * time.Millisecond, TimeoutRetries: 1}
+
+		err := app.Execute(&w, ses, "status")
+		assert.NoError(t, err)
+		assert.Equal(t, "online\n", w.String())
+		assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	})
+
+	// Test retries of all commands are capped by the retry budget.
+	t.Run("retry budget", func(t *testing.T) {
+		var requests int32
+
+		serverSlow := rcontest.NewServer(
+			rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+			rcontest.SetCommandHandler(func(c *rcontest.Context) {
+				atomic.AddInt32(&requests, 1)
+				time.Sleep(200 * time.Millisecond)
+				rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, "online").WriteTo(c.Conn())
+			}),
+		)
+		defer serverSlow.Close()
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{
+			Address: serverSlow.Addr(), Password: "password", Timeout: 50 * time.Millisecond,
+			TimeoutRetries: 2, RetryBudget: 3, SkipErrors: true,
+		}
+
+		err := app.Execute(&w, ses, "status", "status", "status")
+		assert.NoError(t, err)
+		assert.Equal(t, 3, strings.Count(w.String(), "i/o timeout"))
+		// Three commands and three retries instead of six.
+		assert.Equal(t, int32(6), atomic.LoadInt32(&requests))
+	})
+
+	// Test late response of timed out command is not read as the response
+	// of the next command.
+	t.Run("late response", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", Timeout: 100 * time.Millisecond, SkipErrors: true}
+
+		err := app.Execute(&w, ses, "sleep", "help")
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "i/o timeout")
+		assert.Contains(t, w.String(), "Can I help you?")
+		assert.NotContains(t, w.String(), "awake")
+	})
+
+	// Test the deadline is refreshed before each command so the batch
+	// lasting longer than the timeout doesn't fail.
+	t.Run("deadline per command", func(t *testing.T) {
+		serverSlow := rcontest.NewServer(
+			rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+			rcontest.SetCommandHandler(func(c *rcontest.Context) {
+				time.Sleep(50 * time.Millisecond)
+				rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, "ok").WriteTo(c.Conn())
+			}),
+		)
+		defer serverSlow.Close()
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverSlow.Addr(), Password: "password", Timeout: 200 * time.Millisecond}
+
+		commands := []string{"a", "b", "c", "d", "e", "f"}
+
+		err := app.Execute(&w, ses, commands...)
+		assert.NoError(t, err)
+		assert.Equal(t, len(commands), strings.Count(w.String(), "ok\n"))
+	})
+
+	// Test log directory is created unless disabled.
+	t.Run("log mkdir", func(t *testing.T) {
+		logDir := "rcon-test-logs"
+		defer os.RemoveAll(logDir)
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", Log: logDir + "/rcon.log", NoLogMkdir: true}
+
+		err := app.Execute(&w, ses, "help")
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "log: log directory does not exist: "+logDir)
+		assert.NoDirExists(t, logDir)
+
+		ses.NoLogMkdir = false
+
+		err = app.Execute(&w, ses, "help")
+		assert.NoError(t, err)
+		assert.FileExists(t, logDir+"/rcon.log")
+	})
+
+	// Test only the last response is printed.
+	t.Run("last only", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", LastOnly: true}
+
+		err := app.Execute(&w, ses, "prime", "help")
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+	})
+
+	// Test warning about commands slower than the threshold.
+	t.Run("warn slow", func(t *testing.T) {
+		w := bytes.Buffer{}
+		stderr := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		app.SetStderr(&stderr)
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", WarnSlow: time.Hour}
+
+		err := app.Execute(&w, ses, "help")
+		assert.NoError(t, err)
+		assert.Empty(t, stderr.String())
+
+		ses.WarnSlow = time.Nanosecond
+
+		err = app.Execute(&w, ses, "help")
+		assert.NoError(t, err)
+		assert.Contains(t, stderr.String(), `warning: slow command "help" took`)
+		assert.Equal(t, "Can I help you?\nCan I help you?\n", w.String())
+	})
+
+	// Test commands are printed as headers instead of separators.
+	t.Run("labels", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", Labels: true}
+
+		err := app.Execute(&w, ses, "help", "status")
+		assert.NoError(t, err)
+		assert.Equal(t, "=== help ===\nCan I help you?\n=== status ===\nunknown command\n", w.String())
+
+		w.Reset()
+
+		ses.OutputFormat = executor.OutputFormatJSON
+
+		err = app.Execute(&w, ses, "help")
+		assert.NoError(t, err)
+		assert.NotContains(t, w.String(), "===")
+	})
+
+	// Test responses are joined into one response.
+	t.Run("merge responses", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", MergeResponses: true}
+
+		err := app.Execute(&w, ses, "help", "status")
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\nunknown command\n", w.String())
+
+		w.Reset()
+
+		ses.OutputFormat = executor.OutputFormatJSON
+
+		err = app.Execute(&w, ses, "help", "status")
+		assert.NoError(t, err)
+		assert.Equal(t, `{"command":"help; status","response":"Can I help you?\nunknown command"}`+"\n", w.String())
+	})
+
+	// Test commands chained with && and ; operators.
+	t.Run("chaining", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", Chaining: true, DenyPatterns: []string{"^kick"}}
+
+		err := app.Execute(&w, ses, "help && status")
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\nunknown command\n", w.String())
+
+		w.Reset()
+
+		err = app.Execute(&w, ses, "kick Steve && help")
+		assert.ErrorIs(t, err, executor.ErrCommandDenied)
+		assert.Empty(t, w.String())
+
+		w.Reset()
+
+		err = app.Execute(&w, ses, "kick Steve && help; status")
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), executor.ErrCommandDenied.Error())
+		assert.NotContains(t, w.String(), "Can I help you?")
+		assert.Contains(t, w.String(), "unknown command\n")
+
+		w.Reset()
+
+		ses.Chaining = false
+
+		err = app.Execute(&w, ses, "help && status")
+		assert.NoError(t, err)
+		assert.Equal(t, "unknown command\n", w.String())
+	})
+
 	// Test empty command.
 	t.Run("empty command", func(t *testing.T) {
 		w := bytes.Buffer{}
@@ -242,6 +610,70 @@ func TestExecute(t *testing.T) {
 		assert.Equal(t, MockCommandStatusResponseTextWebRCON, result)
 	})
 
+	// Test dial error is printed and skipped with skip errors.
+	t.Run("skip dial error", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "wrong", SkipErrors: true}
+
+		err := app.Execute(&w, ses, "help")
+		assert.NoError(t, err)
+		assert.Equal(t, "execute: auth: rcon: authentication failed\n", w.String())
+	})
+
+	// Test connection without authentication.
+	t.Run("no auth", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		err := app.Execute(&w, &config.Session{Address: serverRCON.Addr(), NoAuth: true}, "help")
+		assert.ErrorIs(t, err, executor.ErrNoAuthUnsupported)
+		assert.Contains(t, err.Error(), "by rcon protocol")
+
+		// Anonymous server serves commands on the root path.
+		serverAnonymous := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r.URL.Path = "/password"
+			handlersWebRCON().ServeHTTP(w, r)
+		}))
+		defer serverAnonymous.Close()
+
+		ses := &config.Session{
+			Address: serverAnonymous.Listener.Addr().String(), Password: "ignored", Type: config.ProtocolWebRCON, NoAuth: true,
+		}
+
+		err = app.Execute(&w, ses, "status")
+		assert.NoError(t, err)
+		assert.Equal(t, MockCommandStatusResponseTextWebRCON+"\n", w.String())
+	})
+
+	// Test responses are written only to the log.
+	t.Run("log only", func(t *testing.T) {
+		logFileName := "tmp/log-only.log"
+		defer func() {
+			os.RemoveAll("tmp")
+		}()
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", Log: logFileName, LogOnly: true}
+
+		err := app.Execute(&w, ses, "help", "help")
+		assert.NoError(t, err)
+		assert.Empty(t, w.String())
+
+		data, err := os.ReadFile(logFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, strings.Count(string(data), "Can I help you?"))
+	})
+
 	// Positive test Execute func with log.
 	t.Run("no error with log", func(t *testing.T) {
 		w := bytes.Buffer{}
@@ -256,6 +688,32 @@ func TestExecute(t *testing.T) {
 		assert.NoError(t, err)
 	})
 
+	// Test buffered log is written on close.
+	t.Run("log flush interval", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		logFileName := "rcon-test-buffered.log"
+		defer os.Remove(logFileName)
+
+		app := executor.NewExecutor(nil, &w, "")
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", Log: logFileName, LogFlushInterval: time.Hour}
+
+		err := app.Execute(&w, ses, "help", "status")
+		assert.NoError(t, err)
+
+		data, err := os.ReadFile(logFileName)
+		assert.NoError(t, err)
+		assert.Empty(t, string(data))
+
+		assert.NoError(t, app.Close())
+
+		data, err = os.ReadFile(logFileName)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), "Can I help you?")
+		assert.Contains(t, string(data), "unknown command")
+	})
+
 	if run := getVar("TEST_PZ_SERVER", "false"); run == "true" {
 		addr := getVar("TEST_PZ_SERVER_ADDR", "127.0.0.1:16260")
 		password := getVar("TEST_PZ_SERVER_PASSWORD", "docker")
@@ -548,9 +1006,6 @@ func TestInteractive(t *testing.T) {
 	t.Run("long command", func(t *testing.T) {
 		r := bytes.Buffer{}
 		r.WriteString("\n")
-		r.WriteString(serverRCON.Addr() + "\n")
-		r.WriteString("password" + "\n")
-		r.WriteString(config.ProtocolRCON + "\n")
 		r.WriteString(string(make([]byte, 1001)) + "\n")
 		r.WriteString("unknown command" + "\n")
 		r.WriteString(executor.CommandQuit + "\n")
@@ -564,14 +1019,11 @@ func TestInteractive(t *testing.T) {
 		assert.EqualError(t, err, "execute: command too long")
 	})
 
-	// Test get Interactive commands RCON.
-	t.Run("get commands rcon", func(t *testing.T) {
+	// Test skip errors keeps the session alive.
+	t.Run("skip errors", func(t *testing.T) {
 		r := bytes.Buffer{}
-		r.WriteString(serverRCON.Addr() + "\n")
-		r.WriteString("password" + "\n")
-		r.WriteString(config.ProtocolRCON + "\n")
+		r.WriteString(string(make([]byte, 1001)) + "\n")
 		r.WriteString("help" + "\n")
-		r.WriteString("unknown command" + "\n")
 		r.WriteString(executor.CommandQuit + "\n")
 
 		w := bytes.Buffer{}
@@ -579,18 +1031,22 @@ func TestInteractive(t *testing.T) {
 		app := executor.NewExecutor(&r, &w, "")
 		defer app.Close()
 
-		err := app.Interactive(&r, &w, &config.Session{})
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", Type: config.ProtocolRCON, SkipErrors: true}
+
+		err := app.Interactive(&r, &w, ses)
 		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "execute: command too long")
+		assert.Contains(t, w.String(), "Can I help you?")
 	})
 
-	// Test get Interactive commands TELNET.
-	t.Run("get commands telnet", func(t *testing.T) {
+	// Test entering interactive mode after the command file.
+	t.Run("interactive from file", func(t *testing.T) {
+		commandFileName := "rcon-test-interactive-commands.txt"
+		createFile(commandFileName, "help\n")
+		defer os.Remove(commandFileName)
+
 		r := bytes.Buffer{}
-		r.WriteString(serverTELNET.Addr() + "\n")
-		r.WriteString("password" + "\n")
-		r.WriteString(config.ProtocolTELNET + "\n")
-		r.WriteString("help" + "\n")
-		r.WriteString("unknown command" + "\n")
+		r.WriteString("unknown" + "\n")
 		r.WriteString(executor.CommandQuit + "\n")
 
 		w := bytes.Buffer{}
@@ -598,39 +1054,770 @@ func TestInteractive(t *testing.T) {
 		app := executor.NewExecutor(&r, &w, "")
 		defer app.Close()
 
-		err := app.Interactive(&r, &w, &config.Session{})
+		args := []string{os.Args[0], "-a=" + serverRCON.Addr(), "-p=password", "-f=" + commandFileName, "--interactive-from-file"}
+
+		err := app.Run(args)
 		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\nWaiting commands for "+serverRCON.Addr()+" (or type :q to exit)\n> unknown command\n> ", w.String())
 	})
 
-	// Test get Interactive commands WEB RCON.
-	t.Run("get commands web", func(t *testing.T) {
+	// Test JSON output format prints only JSON responses to stdout.
+	t.Run("json output", func(t *testing.T) {
+		r := bytes.Buffer{}
+		r.WriteString("help" + "\n")
+		r.WriteString("unknown" + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+		stderr := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		app.SetStderr(&stderr)
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", OutputFormat: executor.OutputFormatJSON}
+
+		err := app.Interactive(&r, &w, ses)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"command":"help","response":"Can I help you?"}`+"\n"+
+			`{"command":"unknown","response":"unknown command"}`+"\n", w.String())
+		assert.Equal(t, "Waiting commands for "+serverRCON.Addr()+" (or type :q to exit)\n> > > ", stderr.String())
+	})
+
+	// Test type of the config environment is resolved before interactive
+	// mode, so the type is not asked.
+	t.Run("type from config env", func(t *testing.T) {
+		configFileName := "rcon-test-interactive-type.yaml"
+		createFile(configFileName, fmt.Sprintf(ConfigLayoutYAML, "7dtd", serverTELNET.Addr(), "password", "", config.ProtocolTELNET))
+		defer os.Remove(configFileName)
+
+		r := bytes.Buffer{}
+		r.WriteString("help" + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		err := app.Run([]string{os.Args[0], "-c=" + configFileName, "-e=7dtd"})
+		assert.NoError(t, err)
+		assert.NotContains(t, w.String(), "Enter protocol type")
+		assert.Contains(t, w.String(), "Can I help you?")
+	})
+
+	// Test switching config environment in the session.
+	t.Run("switch env", func(t *testing.T) {
+		serverOther := rcontest.NewServer(
+			rcontest.SetSettings(rcontest.Settings{Password: "other"}),
+			rcontest.SetCommandHandler(func(c *rcontest.Context) {
+				rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, "other server").WriteTo(c.Conn())
+			}),
+		)
+		defer serverOther.Close()
+
+		configFileName := "rcon-test-switch.yaml"
+		createFile(configFileName, fmt.Sprintf(ConfigLayoutYAML, "main", serverRCON.Addr(), "password", "", "rcon")+"\n"+
+			fmt.Sprintf(ConfigLayoutYAML, "other", serverOther.Addr(), "other", "", "rcon"))
+
+		defer os.Remove(configFileName)
+
+		r := bytes.Buffer{}
+		r.WriteString("help\n")
+		r.WriteString(executor.CommandEnv + " missing\n")
+		r.WriteString(executor.CommandEnv + " oth\n")
+		r.WriteString("help\n")
+		r.WriteString(executor.CommandEnv + " main\n")
+		r.WriteString("help\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+		events := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		app.SetEvents(&events)
+		defer app.Close()
+
+		err := app.Run([]string{os.Args[0], "-c=" + configFileName, "-e=main"})
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "> Can I help you?\n")
+		assert.Contains(t, w.String(), `config: environment not found "missing"`)
+		assert.Contains(t, w.String(), "Target: "+serverOther.Addr()+" (env: other, type: rcon)\n> other server\n")
+		assert.Contains(t, w.String(), "Target: "+serverRCON.Addr()+" (env: main, type: rcon)\n> Can I help you?\n")
+
+		// Test the connection to the previous environment is reused.
+		assert.Equal(t, 2, strings.Count(events.String(), executor.EventDialStart))
+	})
+
+	// Test the timeout and game preset of the switched environment replace
+	// ones of the previous environment.
+	t.Run("switch env defaults", func(t *testing.T) {
+		configFileName := "rcon-test-switch-defaults.yaml"
+		createFile(configFileName, fmt.Sprintf(ConfigLayoutYAML, "main", serverRCON.Addr(), "password", "", "rcon")+
+			"\n  timeout: 500ms\n"+
+			fmt.Sprintf(ConfigLayoutYAML, "slow", serverRCON.Addr(), "password", "", "rcon")+"\n"+
+			"7dtd:\n  address: "+serverTELNET.Addr()+"\n  password: password\n  game: 7dtd\n")
+
+		defer os.Remove(configFileName)
+
+		r := bytes.Buffer{}
+		r.WriteString(executor.CommandEnv + " slow\n")
+		r.WriteString("sleep\n")
+		r.WriteString(executor.CommandEnv + " 7dtd\n")
+		r.WriteString("help\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		err := app.Run([]string{os.Args[0], "-c=" + configFileName, "-e=main"})
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "Target: "+serverRCON.Addr()+" (env: slow, type: rcon)\n> awake\n")
+		assert.Contains(t, w.String(), "Target: "+serverTELNET.Addr()+" (env: 7dtd, type: telnet)\n")
+		assert.Contains(t, w.String(), "Can I help you?")
+	})
+
+	// Test deny patterns and SSH tunnel of the switched environment are
+	// applied and don't leak to the next one.
+	t.Run("switch env settings", func(t *testing.T) {
+		configFileName := "rcon-test-switch-settings.yaml"
+		createFile(configFileName, fmt.Sprintf(ConfigLayoutYAML, "main", serverRCON.Addr(), "password", "", "rcon")+"\n"+
+			fmt.Sprintf(ConfigLayoutYAML, "guarded", serverRCON.Addr(), "password", "", "rcon")+
+			"\n  deny_patterns: [\"^help$\"]\n"+
+			fmt.Sprintf(ConfigLayoutYAML, "bastion", serverRCON.Addr(), "password", "", "rcon")+
+			"\n  ssh: admin@127.0.0.1:1\n  ssh_known_hosts: rcon-test-missing-known-hosts\n")
+
+		defer os.Remove(configFileName)
+
+		r := bytes.Buffer{}
+		r.WriteString(executor.CommandEnv + " guarded\n")
+		r.WriteString("help\n")
+		r.WriteString(executor.CommandEnv + " bastion\n")
+		r.WriteString(executor.CommandEnv + " main\n")
+		r.WriteString("help\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		err := app.Run([]string{os.Args[0], "-c=" + configFileName, "-e=main", "-s"})
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "(env: guarded, type: rcon)\n> execute: "+executor.ErrCommandDenied.Error())
+		assert.Contains(t, w.String(), "> auth: ssh: known hosts:")
+		assert.Contains(t, w.String(), "(env: main, type: rcon)\n> Can I help you?\n")
+	})
+
+	// Test reloading the edited config in the session.
+	t.Run("reload", func(t *testing.T) {
+		serverOther := rcontest.NewServer(
+			rcontest.SetSettings(rcontest.Settings{Password: "other"}),
+			rcontest.SetCommandHandler(func(c *rcontest.Context) {
+				rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, "other server").WriteTo(c.Conn())
+			}),
+		)
+		defer serverOther.Close()
+
+		configFileName := "rcon-test-reload.yaml"
+		createFile(configFileName, fmt.Sprintf(ConfigLayoutYAML, "main", serverRCON.Addr(), "password", "", "rcon"))
+
+		defer os.Remove(configFileName)
+
+		r, input := io.Pipe()
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(r, &w, "")
+		defer app.Close()
+
+		done := make(chan error)
+		go func() {
+			done <- app.Run([]string{os.Args[0], "-c=" + configFileName, "-e=main"})
+		}()
+
+		_, _ = io.WriteString(input, "help\n")
+
+		// The config is edited before the reload command is sent.
+		createFile(configFileName, fmt.Sprintf(ConfigLayoutYAML, "main", serverOther.Addr(), "other", "", "rcon"))
+
+		_, _ = io.WriteString(input, executor.CommandReload+"\nhelp\n"+executor.CommandQuit+"\n")
+
+		assert.NoError(t, <-done)
+		assert.Contains(t, w.String(), "> Can I help you?\n")
+		assert.Contains(t, w.String(), "Config reloaded (env: main)\n> other server\n")
+	})
+
+	// Test known commands of the game are completed with ones of the help
+	// output.
+	t.Run("complete", func(t *testing.T) {
+		serverHelp := rcontest.NewServer(
+			rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+			rcontest.SetCommandHandler(func(c *rcontest.Context) {
+				rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID,
+					"/ban <targets> [<reason>]/banlist [ips|players]/banmod reload").WriteTo(c.Conn())
+			}),
+		)
+		defer serverHelp.Close()
+
+		r := bytes.Buffer{}
+		r.WriteString(executor.CommandComplete + " ban\n")
+		r.WriteString(executor.CommandComplete + " banm\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{
+			Address: serverHelp.Addr(), Password: "password", Type: config.ProtocolRCON, Game: executor.GameMinecraft,
+			NoBanner: true,
+		}
+
+		err := app.Interactive(&r, &w, ses)
+		assert.NoError(t, err)
+		assert.Equal(t, "> ban\nban-ip\nbanlist\nbanmod\n> banmod\n> ", w.String())
+	})
+
+	// Test the help command of completion is refused by deny patterns.
+	t.Run("complete denied", func(t *testing.T) {
+		r := bytes.Buffer{}
+		r.WriteString(executor.CommandComplete + " ban\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+		events := syncBuffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		app.SetEvents(&events)
+		defer app.Close()
+
+		ses := &config.Session{
+			Address: serverRCON.Addr(), Password: "password", Type: config.ProtocolRCON, Game: executor.GameMinecraft,
+			NoBanner: true, DenyPatterns: []string{"^help$"},
+		}
+
+		err := app.Interactive(&r, &w, ses)
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "complete: "+executor.ErrCommandDenied.Error())
+		assert.NotContains(t, events.String(), executor.EventCommandSent)
+	})
+
+	// Test commands are not completed without game preset.
+	t.Run("complete no game", func(t *testing.T) {
+		r := bytes.Buffer{}
+		r.WriteString(executor.CommandComplete + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", Type: config.ProtocolRCON}
+
+		err := app.Interactive(&r, &w, ses)
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), executor.ErrNoGame.Error())
+	})
+
+	// Test edited deny patterns are applied and edited SSH tunnel
+	// reestablishes the connection on reload.
+	t.Run("reload settings", func(t *testing.T) {
+		configFileName := "rcon-test-reload-settings.yaml"
+		layout := fmt.Sprintf(ConfigLayoutYAML, "main", serverRCON.Addr(), "password", "", "rcon")
+		createFile(configFileName, layout)
+
+		defer os.Remove(configFileName)
+
+		r, input := io.Pipe()
+		w := syncBuffer{}
+
+		app := executor.NewExecutor(r, &w, "")
+		defer app.Close()
+
+		done := make(chan error)
+		go func() {
+			done <- app.Run([]string{os.Args[0], "-c=" + configFileName, "-e=main", "-s"})
+		}()
+
+		_, _ = io.WriteString(input, "help\n")
+		assert.Eventually(t, func() bool { return strings.Contains(w.String(), "Can I help you?") },
+			time.Second, 10*time.Millisecond)
+
+		createFile(configFileName, layout+"\n  deny_patterns: [\"^help$\"]\n")
+		_, _ = io.WriteString(input, executor.CommandReload+"\nhelp\n")
+
+		assert.Eventually(t, func() bool { return strings.Contains(w.String(), executor.ErrCommandDenied.Error()) },
+			time.Second, 10*time.Millisecond)
+
+		createFile(configFileName, layout+"\n  ssh: admin@127.0.0.1:1\n  ssh_known_hosts: rcon-test-missing-known-hosts\n")
+		_, _ = io.WriteString(input, executor.CommandReload+"\n"+executor.CommandQuit+"\n")
+
+		assert.NoError(t, <-done)
+		assert.Contains(t, w.String(), "Config reloaded (env: main)\n> execute: "+executor.ErrCommandDenied.Error())
+		assert.Contains(t, w.String(), "> auth: ssh: known hosts:")
+	})
+
+	// Test saving executed commands as the command file.
+	t.Run("save", func(t *testing.T) {
+		commandFileName := "rcon-test-session.rcon"
+		defer os.Remove(commandFileName)
+
+		r := bytes.Buffer{}
+		r.WriteString("help\n")
+		r.WriteString("status\n")
+		r.WriteString(executor.CommandSave + " " + commandFileName + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		err := app.Run([]string{os.Args[0], "-a=" + serverRCON.Addr(), "-p=password"})
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "Saved 2 commands to "+commandFileName+"\n")
+
+		commands, err := executor.ReadCommandFile(commandFileName, "")
+		assert.NoError(t, err)
+		assert.Equal(t, executor.NewCommands("help", "status"), commands)
+	})
+
+	// Test pager is disabled when output is not a terminal.
+	t.Run("pager not a terminal", func(t *testing.T) {
+		r := bytes.Buffer{}
+		r.WriteString("help\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", Type: config.ProtocolRCON, Pager: true}
+
+		err := app.Interactive(&r, &w, ses)
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "> Can I help you?\n> ")
+	})
+
+	// Test multiline command with continuation character.
+	t.Run("continuation", func(t *testing.T) {
+		r := bytes.Buffer{}
+		r.WriteString("he" + executor.CommandContinuation + "\n")
+		r.WriteString("l" + executor.CommandContinuation + "\n")
+		r.WriteString("p" + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", Type: config.ProtocolRCON}
+
+		err := app.Interactive(&r, &w, ses)
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "> ... ... Can I help you?\n> ")
+	})
+
+	// Transcript of the session is written to the file on exit.
+	t.Run("transcript", func(t *testing.T) {
+		transcriptFileName := "rcon-test-transcript.txt"
+		defer os.Remove(transcriptFileName)
+
+		r := bytes.Buffer{}
+		r.WriteString("help" + "\n")
+		r.WriteString("unknown" + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{
+			Address: serverRCON.Addr(), Password: "password", Type: config.ProtocolRCON, Transcript: transcriptFileName,
+		}
+
+		err := app.Interactive(&r, &w, ses)
+		assert.NoError(t, err)
+
+		data, err := os.ReadFile(transcriptFileName)
+		assert.NoError(t, err)
+
+		text := string(data)
+		assert.True(t, strings.HasPrefix(text, "Session with "+serverRCON.Addr()+" started at "), text)
+		assert.Contains(t, text, "] > help\nCan I help you?\n[")
+		assert.Contains(t, text, "] > unknown\nunknown command\n\nSession ended at ")
+		assert.NotContains(t, text, executor.CommandQuit)
+	})
+
+	// Destructive command from non-terminal input is refused without
+	// consuming the next line.
+	t.Run("destructive", func(t *testing.T) {
+		r := bytes.Buffer{}
+		r.WriteString("stop" + "\n")
+		r.WriteString("help" + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", Type: config.ProtocolRCON, NoBanner: true}
+
+		err := app.Interactive(&r, &w, ses)
+		assert.NoError(t, err)
+		assert.Equal(t, "> execution is not confirmed: stop: use --yes to send destructive commands from "+
+			"non-terminal input\n> Can I help you?\n> ", w.String())
+	})
+
+	// Destructive command is sent with --yes.
+	t.Run("destructive yes", func(t *testing.T) {
+		r := bytes.Buffer{}
+		r.WriteString("/ban Steve" + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", Type: config.ProtocolRCON, NoBanner: true, Yes: true}
+
+		err := app.Interactive(&r, &w, ses)
+		assert.NoError(t, err)
+		assert.Equal(t, "> unknown command\n> ", w.String())
+	})
+
+	// Banner is not printed, prompts are kept.
+	t.Run("no banner", func(t *testing.T) {
+		r := bytes.Buffer{}
+		r.WriteString("help" + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", Type: config.ProtocolRCON, NoBanner: true}
+
+		err := app.Interactive(&r, &w, ses)
+		assert.NoError(t, err)
+		assert.Equal(t, "> Can I help you?\n> ", w.String())
+	})
+
+	// Keepalive command is sent while waiting for input, its response is
+	// not printed.
+	t.Run("keepalive", func(t *testing.T) {
+		r, pw := io.Pipe()
+
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			_, _ = pw.Write([]byte("help\n" + executor.CommandQuit + "\n"))
+			_ = pw.Close()
+		}()
+
+		w := bytes.Buffer{}
+		events := bytes.Buffer{}
+
+		app := executor.NewExecutor(r, &w, "")
+		app.SetEvents(&events)
+		defer app.Close()
+
+		ses := &config.Session{
+			Address: serverRCON.Addr(), Password: "password", Type: config.ProtocolRCON,
+			InteractiveKeepalive: 20 * time.Millisecond,
+		}
+
+		err := app.Interactive(r, &w, ses)
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "> Can I help you?\n> ")
+		assert.NotContains(t, w.String(), "unknown command")
+		assert.Contains(t, events.String(), `"command":"echo"`)
+	})
+
+	// Test missing connection details are not prompted outside terminal.
+	t.Run("prompt not a terminal", func(t *testing.T) {
+		r := bytes.Buffer{}
+		r.WriteString(serverRCON.Addr() + "\n")
+		r.WriteString("password" + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		err := app.Interactive(&r, &w, &config.Session{})
+		assert.ErrorIs(t, err, executor.ErrEmptyAddress)
+
+		err = app.Interactive(&r, &w, &config.Session{Address: serverRCON.Addr()})
+		assert.ErrorIs(t, err, executor.ErrEmptyPassword)
+		assert.Empty(t, w.String())
+	})
+
+	// Test get Interactive commands RCON.
+	t.Run("get commands rcon", func(t *testing.T) {
+		r := bytes.Buffer{}
+		r.WriteString("help" + "\n")
+		r.WriteString("unknown command" + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		err := app.Interactive(&r, &w, &config.Session{Address: serverRCON.Addr(), Password: "password", Type: config.ProtocolRCON})
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "> Can I help you?\n> unknown command\n> ")
+		assert.Equal(t, 1, strings.Count(w.String(), "unknown command\n"))
+	})
+
+	// Test get Interactive commands TELNET.
+	t.Run("get commands telnet", func(t *testing.T) {
+		r := bytes.Buffer{}
+		r.WriteString("help" + "\n")
+		r.WriteString("unknown command" + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		err := app.Interactive(&r, &w, &config.Session{Address: serverTELNET.Addr(), Password: "password", Type: config.ProtocolTELNET})
+		assert.NoError(t, err)
+	})
+
+	// Test TELNET Interactive commands are logged.
+	t.Run("log commands telnet", func(t *testing.T) {
+		logFileName := "rcon-test-telnet.log"
+		defer os.Remove(logFileName)
+
+		r := bytes.Buffer{}
+		r.WriteString("help" + "\n")
+		r.WriteString(executor.CommandQuit + "\n")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverTELNET.Addr(), Password: "password", Type: config.ProtocolTELNET, Log: logFileName}
+
+		err := app.Interactive(&r, &w, ses)
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "Can I help you?")
+
+		logs, err := os.ReadFile(logFileName)
+		assert.NoError(t, err)
+		assert.Contains(t, string(logs), "Can I help you?")
+	})
+
+	// Test get Interactive commands WEB RCON.
+	t.Run("get commands web", func(t *testing.T) {
 		r := bytes.Buffer{}
-		r.WriteString(serverWebRCON.Listener.Addr().String() + "\n")
-		r.WriteString("password" + "\n")
-		r.WriteString(config.ProtocolWebRCON + "\n")
 		r.WriteString("status" + "\n")
 		r.WriteString("unknown command" + "\n")
 		r.WriteString(executor.CommandQuit + "\n")
 
-		w := bytes.Buffer{}
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(&r, &w, "")
+		defer app.Close()
+
+		err := app.Interactive(&r, &w, &config.Session{Address: serverWebRCON.Listener.Addr().String(), Password: "password", Type: config.ProtocolWebRCON})
+		assert.NoError(t, err)
+	})
+}
+
+func TestNewExecutor(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	// Test getting address and password from args. Config ang log are not used.
+	t.Run("getting address and password from args", func(t *testing.T) {
+		r := &bytes.Buffer{}
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr())
+		args = append(args, "-p="+"password")
+		args = append(args, "help")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+	})
+
+	// Test help reflects the name and usage of the embedding binary.
+	t.Run("custom name", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(&bytes.Buffer{}, w, "")
+		defer app.Close()
+
+		app.SetName("mytool")
+		app.SetUsage("Manage my game servers")
+
+		err := app.Run([]string{os.Args[0], "--help"})
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "mytool - Manage my game servers")
+		assert.Contains(t, w.String(), "mytool -a 127.0.0.1:16260 -p password command1 command2")
+	})
+
+	// Test getting address and password from config. Log is not used.
+	t.Run("getting address and password from args with log", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		logFileName := "rcon-test.log"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "password", logFileName, "")
+		createFile(configFileName, stringBody)
+
+		defer func() {
+			os.Remove(logFileName)
+			os.Remove(configFileName)
+		}()
+
+		r := &bytes.Buffer{}
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-c="+configFileName)
+		args = append(args, "help")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+	})
+
+	// Test empty address and password. Log is not used.
+	t.Run("empty address and password", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		logFileName := "rcon-test.log"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, "", "", logFileName, "")
+		createFile(configFileName, stringBody)
+
+		defer func() {
+			os.Remove(logFileName)
+			os.Remove(configFileName)
+		}()
+
+		r := &bytes.Buffer{}
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		// Hack to use os.Args[0] in go run
+		args[0] = ""
+		args = append(args, "-c="+configFileName)
+		args = append(args, "help")
+
+		err := app.Run(args)
+		assert.EqualError(t, err, "cli: address is not set: to set address add -a host:port")
+	})
+
+	// Test empty password. Log is not used.
+	t.Run("empty password", func(t *testing.T) {
+		configFileName := "rcon-test-local.yaml"
+		logFileName := "rcon-test.log"
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "", logFileName, "")
+		createFile(configFileName, stringBody)
+
+		defer func() {
+			os.Remove(logFileName)
+			os.Remove(configFileName)
+		}()
+
+		r := &bytes.Buffer{}
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		// Hack to use os.Args[0] in go run
+		args[0] = ""
+		args = append(args, "-a="+serverRCON.Addr())
+		args = append(args, "-c="+configFileName)
+		args = append(args, "help")
+
+		err := app.Run(args)
+		assert.EqualError(t, err, "cli: password is not set: to set password add -p password")
+	})
+
+	// Test printing target without confirmation in non-terminal mode.
+	t.Run("confirm target", func(t *testing.T) {
+		r := &bytes.Buffer{}
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr())
+		args = append(args, "-p="+"password")
+		args = append(args, "--confirm-target")
+		args = append(args, "help")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Equal(t, "Target: "+serverRCON.Addr()+" (env: default, type: rcon)\nCan I help you?\n", w.String())
+	})
+
+	// Test /dev/null input is not a terminal asking for confirmation.
+	t.Run("confirm target dev null", func(t *testing.T) {
+		r, err := os.Open(os.DevNull)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Close()
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		err = app.Run([]string{os.Args[0], "-a=" + serverRCON.Addr(), "-p=password", "--confirm-target", "help"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Target: "+serverRCON.Addr()+" (env: default, type: rcon)\nCan I help you?\n", w.String())
+	})
+
+	// Test destructive command from /dev/null input is sent without prompt.
+	t.Run("destructive dev null", func(t *testing.T) {
+		r, err := os.Open(os.DevNull)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Close()
+
+		w := &bytes.Buffer{}
 
-		app := executor.NewExecutor(&r, &w, "")
+		app := executor.NewExecutor(r, w, "")
 		defer app.Close()
 
-		err := app.Interactive(&r, &w, &config.Session{})
+		err = app.Run([]string{os.Args[0], "-a=" + serverRCON.Addr(), "-p=password", "stop"})
 		assert.NoError(t, err)
+		assert.Equal(t, "unknown command\n", w.String())
 	})
-}
-
-func TestNewExecutor(t *testing.T) {
-	serverRCON := rcontest.NewServer(
-		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
-		rcontest.SetCommandHandler(handlersRCON),
-	)
-	defer serverRCON.Close()
 
-	// Test getting address and password from args. Config ang log are not used.
-	t.Run("getting address and password from args", func(t *testing.T) {
+	// Test printing effective config without connecting.
+	t.Run("print config", func(t *testing.T) {
 		r := &bytes.Buffer{}
 		w := &bytes.Buffer{}
 
@@ -640,50 +1827,338 @@ func TestNewExecutor(t *testing.T) {
 		args := os.Args[0:1]
 		args = append(args, "-a="+serverRCON.Addr())
 		args = append(args, "-p="+"password")
+		args = append(args, "--print-config")
 		args = append(args, "help")
 
 		err := app.Run(args)
 		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "address: "+serverRCON.Addr()+"\n")
+		assert.Contains(t, w.String(), "password: '"+config.MaskedPassword+"'\n")
+		assert.Contains(t, w.String(), "timeout: 10s\n")
+		assert.NotContains(t, w.String(), "Can I help you?")
 	})
 
-	// Test getting address and password from config. Log is not used.
-	t.Run("getting address and password from args with log", func(t *testing.T) {
-		configFileName := "rcon-test-local.yaml"
-		logFileName := "rcon-test.log"
-		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "password", logFileName, "")
-		createFile(configFileName, stringBody)
+	// Test variables show resolution details and mask the password.
+	t.Run("print variables", func(t *testing.T) {
+		configFileName := "rcon-test-variables.yaml"
+		createFile(configFileName, fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "password", "", "telnet"))
+		defer os.Remove(configFileName)
 
-		defer func() {
-			os.Remove(logFileName)
-			os.Remove(configFileName)
-		}()
+		w := &bytes.Buffer{}
 
-		r := &bytes.Buffer{}
+		app := executor.NewExecutor(&bytes.Buffer{}, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{os.Args[0], "-c=" + configFileName, "--variables"})
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), `"password": "`+config.MaskedPassword+`"`)
+		assert.NotContains(t, w.String(), `"password": "password"`)
+		assert.Contains(t, w.String(), "Config file "+configFileName+": found\n")
+		assert.Contains(t, w.String(), "Effective protocol: telnet\n")
+		assert.Contains(t, w.String(), "Address source: config\n")
+	})
+
+	// Test game preset sets the type and the default port.
+	t.Run("game preset", func(t *testing.T) {
 		w := &bytes.Buffer{}
 
-		app := executor.NewExecutor(r, w, "")
+		app := executor.NewExecutor(&bytes.Buffer{}, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{os.Args[0], "-a=127.0.0.1", "-p=password", "--game=rust", "--print-config", "help"})
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "address: 127.0.0.1:28016\n")
+		assert.Contains(t, w.String(), "type: "+config.ProtocolWebRCON+"\n")
+
+		// Explicit type and port take precedence over the preset.
+		w.Reset()
+
+		appTyped := executor.NewExecutor(&bytes.Buffer{}, w, "")
+		defer appTyped.Close()
+
+		err = appTyped.Run([]string{os.Args[0], "-a=127.0.0.1:1234", "-p=password", "-t=rcon", "--game=rust", "--print-config", "help"})
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "address: 127.0.0.1:1234\n")
+		assert.Contains(t, w.String(), "type: "+config.ProtocolRCON+"\n")
+	})
+
+	// Test colors are stripped when output is not a terminal.
+	t.Run("color detection", func(t *testing.T) {
+		serverColored := rcontest.NewServer(
+			rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+			rcontest.SetCommandHandler(func(c *rcontest.Context) {
+				rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, "§aonline").WriteTo(c.Conn())
+			}),
+		)
+		defer serverColored.Close()
+
+		outputs := make(map[bool]string)
+
+		for _, force := range []bool{false, true} {
+			w := &bytes.Buffer{}
+
+			app := executor.NewExecutor(&bytes.Buffer{}, w, "")
+
+			args := os.Args[0:1]
+			args = append(args, "-a="+serverColored.Addr(), "-p=password", fmt.Sprintf("--force-color=%t", force), "list")
+
+			err := app.Run(args)
+			assert.NoError(t, err)
+
+			outputs[force] = w.String()
+
+			app.Close()
+		}
+
+		assert.NotContains(t, outputs[false], "§")
+		assert.NotContains(t, outputs[false], "\x1b[")
+		assert.NotEqual(t, outputs[false], outputs[true])
+	})
+
+	// Test color palette of the color mode.
+	t.Run("color mode", func(t *testing.T) {
+		serverColored := rcontest.NewServer(
+			rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+			rcontest.SetCommandHandler(func(c *rcontest.Context) {
+				rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, "§aonline").WriteTo(c.Conn())
+			}),
+		)
+		defer serverColored.Close()
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(&bytes.Buffer{}, w, "")
 		defer app.Close()
 
 		args := os.Args[0:1]
-		args = append(args, "-c="+configFileName)
-		args = append(args, "help")
+		args = append(args, "-a="+serverColored.Addr(), "-p=password", "--force-color", "--color-mode=truecolor", "list")
 
 		err := app.Run(args)
 		assert.NoError(t, err)
+		assert.Equal(t, "\033[38;2;85;255;85monline"+colors.ANSIReset+"\n", w.String())
+
+		args = os.Args[0:1]
+		args = append(args, "-a="+serverColored.Addr(), "-p=password", "--color-mode=88", "list")
+
+		err = app.Run(args)
+		assert.ErrorIs(t, err, executor.ErrUnsupportedColorMode)
 	})
 
-	// Test empty address and password. Log is not used.
-	t.Run("empty address and password", func(t *testing.T) {
+	// Test log line in custom template.
+	t.Run("log template", func(t *testing.T) {
+		logFileName := "rcon-test-template.log"
+		defer os.Remove(logFileName)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(&bytes.Buffer{}, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password", "-l="+logFileName,
+			"--log-template={{.Type}} {{.Command}}: {{.Response}}", "help")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+
+		data, err := os.ReadFile(logFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, "rcon help: Can I help you?\n", string(data))
+
+		args = os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password", "--log-template={{.Command", "help")
+
+		err = app.Run(args)
+		assert.ErrorIs(t, err, logger.ErrInvalidTemplate)
+	})
+
+	// Test connection check without commands.
+	t.Run("connect only", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(&bytes.Buffer{}, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{os.Args[0], "-a=" + serverRCON.Addr(), "-p=password", "--connect-only"})
+		assert.NoError(t, err)
+		assert.Equal(t, "OK\n", w.String())
+
+		w.Reset()
+
+		err = app.Run([]string{os.Args[0], "-a=" + serverRCON.Addr(), "-p=password", "--connect-only", "-q"})
+		assert.NoError(t, err)
+		assert.Empty(t, w.String())
+
+		app2 := executor.NewExecutor(&bytes.Buffer{}, w, "")
+		defer app2.Close()
+
+		err = app2.Run([]string{os.Args[0], "-a=" + serverRCON.Addr(), "-p=wrong", "--connect-only"})
+		assert.ErrorIs(t, err, rcon.ErrAuthFailed)
+		assert.Empty(t, w.String())
+	})
+
+	// Test copying printed output to the output file.
+	t.Run("output file", func(t *testing.T) {
+		outputFileName := "rcon-test-output.txt"
+		defer os.Remove(outputFileName)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(&bytes.Buffer{}, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password", "-O="+outputFileName, "help", "help")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+
+		data, err := os.ReadFile(outputFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, w.String(), string(data))
+		assert.Equal(t, "Can I help you?\n--------\nCan I help you?\n", string(data))
+
+		w.Reset()
+
+		args = os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password", "-O="+outputFileName,
+			"--output-append", "--log-only", "help")
+
+		err = app.Run(args)
+		assert.NoError(t, err)
+		assert.Empty(t, w.String())
+
+		data, err = os.ReadFile(outputFileName)
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n--------\nCan I help you?\nCan I help you?\n", string(data))
+	})
+
+	// Test reading config from stdin.
+	t.Run("config from stdin", func(t *testing.T) {
+		stdin := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "password", "", "")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(strings.NewReader(stdin), w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-c=-", "help")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+
+		// Commands can't be read from consumed stdin.
+		app = executor.NewExecutor(strings.NewReader(stdin), w, "")
+		defer app.Close()
+
+		err = app.Run(append(os.Args[0:1:1], "-c=-"))
+		assert.ErrorIs(t, err, executor.ErrStdinConsumed)
+	})
+
+	// Test unknown config fields are errors in strict mode.
+	t.Run("strict config", func(t *testing.T) {
+		stdin := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "password", "", "") + "\n  pasword: typo\n"
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(strings.NewReader(stdin), w, "")
+		defer app.Close()
+
+		err := app.Run(append(os.Args[0:1:1], "-c=-", "--strict", "help"))
+		assert.ErrorContains(t, err, "pasword")
+		assert.Empty(t, w.String())
+	})
+
+	// Test connection details from prefixed environment variables.
+	t.Run("env prefix", func(t *testing.T) {
+		t.Setenv("MYGAME_"+executor.EnvAddress, serverRCON.Addr())
+		t.Setenv("MYGAME_"+executor.EnvPassword, "password")
+		t.Setenv("MYGAME_"+executor.EnvType, config.ProtocolRCON)
+		t.Setenv(executor.EnvPassword, "wrong")
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(&bytes.Buffer{}, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{os.Args[0], "--env-prefix=MYGAME_", "help"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+
+		// Unprefixed variable is used without the prefix flag.
+		appNoPrefix := executor.NewExecutor(&bytes.Buffer{}, w, "")
+		defer appNoPrefix.Close()
+
+		err = appNoPrefix.Run([]string{os.Args[0], "-a=" + serverRCON.Addr(), "help"})
+		assert.ErrorIs(t, err, rcon.ErrAuthFailed)
+	})
+
+	t.Run("global section", func(t *testing.T) {
+		configFileName := "rcon-test-global.yaml"
+		stringBody := "global:\n  timeout: 7s\n  type: telnet\n  log_format: json\n" +
+			fmt.Sprintf(ConfigLayoutYAML, "rust", serverRCON.Addr(), "password", "", "web")
+		createFile(configFileName, stringBody)
+
+		defer os.Remove(configFileName)
+
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(&bytes.Buffer{}, w, "")
+		defer app.Close()
+
+		err := app.Run([]string{os.Args[0], "-c=" + configFileName, "-e=rust", "--print-config"})
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "timeout: 7s\n")
+		assert.Contains(t, w.String(), "type: web\n")
+		assert.Contains(t, w.String(), "log_format: json\n")
+
+		w.Reset()
+
+		err = app.Run([]string{os.Args[0], "-c=" + configFileName, "-e=rust", "-t=rcon", "--print-config"})
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "type: rcon\n")
+	})
+
+	// Test timeout layering from config environments.
+	t.Run("timeout per env", func(t *testing.T) {
 		configFileName := "rcon-test-local.yaml"
-		logFileName := "rcon-test.log"
-		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, "", "", logFileName, "")
+		stringBody := fmt.Sprintf(ConfigLayoutYAML, "fast", serverRCON.Addr(), "password", "", "") + "\n  timeout: 3s\n" +
+			fmt.Sprintf(ConfigLayoutYAML, "slow", serverRCON.Addr(), "password", "", "") + "\n  timeout: 1m\n"
 		createFile(configFileName, stringBody)
 
-		defer func() {
-			os.Remove(logFileName)
-			os.Remove(configFileName)
-		}()
+		defer os.Remove(configFileName)
+
+		tests := []struct {
+			name     string
+			args     []string
+			expected string
+		}{
+			{"fast", []string{"-e=fast"}, "timeout: 3s\n"},
+			{"slow", []string{"-e=slow"}, "timeout: 1m0s\n"},
+			{"flag overrides env", []string{"-e=slow", "-T=5s"}, "timeout: 5s\n"},
+			{"protocol default", []string{"-a=" + serverRCON.Addr(), "-p=password", "-t=web"}, "timeout: 20s\n"},
+		}
+
+		for _, tt := range tests {
+			r := &bytes.Buffer{}
+			w := &bytes.Buffer{}
+
+			app := executor.NewExecutor(r, w, "")
+
+			args := os.Args[0:1]
+			args = append(args, "-c="+configFileName, "--print-config")
+			args = append(args, tt.args...)
 
+			err := app.Run(args)
+			assert.NoError(t, err, tt.name)
+			assert.Contains(t, w.String(), tt.expected, tt.name)
+		}
+	})
+
+	// Test halt timeout exceeded.
+	t.Run("halt timeout", func(t *testing.T) {
 		r := &bytes.Buffer{}
 		w := &bytes.Buffer{}
 
@@ -691,42 +2166,134 @@ func TestNewExecutor(t *testing.T) {
 		defer app.Close()
 
 		args := os.Args[0:1]
-		// Hack to use os.Args[0] in go run
-		args[0] = ""
-		args = append(args, "-c="+configFileName)
-		args = append(args, "help")
+		args = append(args, "-a="+serverRCON.Addr())
+		args = append(args, "-p="+"password")
+		args = append(args, "--halt-timeout=100ms")
+		args = append(args, "sleep")
 
 		err := app.Run(args)
-		assert.EqualError(t, err, "cli: address is not set: to set address add -a host:port")
+		assert.ErrorIs(t, err, executor.ErrHaltTimeout)
+		assert.Equal(t, executor.ExitCodeHaltTimeout, executor.ExitCode(err))
+
+		// Test the connection is closed once.
+		assert.NoError(t, app.Close())
+		assert.NoError(t, app.Close())
 	})
 
-	// Test empty password. Log is not used.
-	t.Run("empty password", func(t *testing.T) {
-		configFileName := "rcon-test-local.yaml"
-		logFileName := "rcon-test.log"
-		stringBody := fmt.Sprintf(ConfigLayoutYAML, config.DefaultConfigEnv, serverRCON.Addr(), "", logFileName, "")
-		createFile(configFileName, stringBody)
+	// Test halt timeout closes the connection and stops timeout retries of
+	// the command the server never answers.
+	t.Run("halt timeout retries", func(t *testing.T) {
+		w := &bytes.Buffer{}
 
-		defer func() {
-			os.Remove(logFileName)
-			os.Remove(configFileName)
-		}()
+		app := executor.NewExecutor(&bytes.Buffer{}, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr())
+		args = append(args, "-p="+"password")
+		args = append(args, "--halt-timeout=300ms")
+		args = append(args, "--command-timeout-retries=5")
+		args = append(args, "shutdown")
+
+		start := time.Now()
 
+		err := app.Run(args)
+		assert.ErrorIs(t, err, executor.ErrHaltTimeout)
+		assert.Less(t, time.Since(start), 600*time.Millisecond)
+	})
+
+	// Test halt timeout stops endless repeat.
+	t.Run("halt timeout repeat", func(t *testing.T) {
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(&bytes.Buffer{}, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr())
+		args = append(args, "-p="+"password")
+		args = append(args, "--halt-timeout=100ms")
+		args = append(args, "--repeat=30ms")
+		args = append(args, "help")
+
+		err := app.Run(args)
+		assert.ErrorIs(t, err, executor.ErrHaltTimeout)
+		assert.Contains(t, w.String(), "Can I help you?")
+	})
+
+	// Test responses routed to stderr.
+	t.Run("response to stderr", func(t *testing.T) {
 		r := &bytes.Buffer{}
 		w := &bytes.Buffer{}
+		stderr := &bytes.Buffer{}
 
 		app := executor.NewExecutor(r, w, "")
+		app.SetStderr(stderr)
 		defer app.Close()
 
 		args := os.Args[0:1]
-		// Hack to use os.Args[0] in go run
-		args[0] = ""
 		args = append(args, "-a="+serverRCON.Addr())
-		args = append(args, "-c="+configFileName)
+		args = append(args, "-p="+"password")
+		args = append(args, "--response-to="+executor.ResponseToStderr)
 		args = append(args, "help")
 
 		err := app.Run(args)
-		assert.EqualError(t, err, "cli: password is not set: to set password add -p password")
+		assert.NoError(t, err)
+		assert.Empty(t, w.String())
+		assert.Equal(t, "Can I help you?\n", stderr.String())
+	})
+
+	// Test progress lines are printed to stderr unless quiet.
+	t.Run("progress", func(t *testing.T) {
+		w := &bytes.Buffer{}
+		stderr := &bytes.Buffer{}
+
+		app := executor.NewExecutor(&bytes.Buffer{}, w, "")
+		app.SetStderr(stderr)
+		defer app.Close()
+
+		err := app.Run([]string{os.Args[0], "-a=" + serverRCON.Addr(), "-p=password", "--progress", "help", "status"})
+		assert.NoError(t, err)
+		assert.Equal(t, "running: help\nrunning: status\n", stderr.String())
+		assert.Equal(t, "Can I help you?\n--------\nunknown command\n", w.String())
+
+		stderr.Reset()
+
+		appQuiet := executor.NewExecutor(&bytes.Buffer{}, w, "")
+		appQuiet.SetStderr(stderr)
+		defer appQuiet.Close()
+
+		err = appQuiet.Run([]string{os.Args[0], "-a=" + serverRCON.Addr(), "-p=password", "--progress", "-q", "help"})
+		assert.NoError(t, err)
+		assert.Empty(t, stderr.String())
+	})
+
+	// Test the payload is printed after slash stripping and line ending.
+	t.Run("print sent", func(t *testing.T) {
+		stderr := &bytes.Buffer{}
+
+		app := executor.NewExecutor(&bytes.Buffer{}, &bytes.Buffer{}, "")
+		app.SetStderr(stderr)
+		defer app.Close()
+
+		args := []string{
+			os.Args[0], "-a=" + serverRCON.Addr(), "-p=password", "--print-sent",
+			"--strip-leading-slash", "--line-ending=crlf", "/list",
+		}
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Equal(t, "sent: \"list\\r\\n\"\n", stderr.String())
+
+		stderr.Reset()
+
+		appHex := executor.NewExecutor(&bytes.Buffer{}, &bytes.Buffer{}, "")
+		appHex.SetStderr(stderr)
+		defer appHex.Close()
+
+		err = appHex.Run(append(args[:len(args)-1], "--hex", "/list"))
+		assert.NoError(t, err)
+		assert.Equal(t, "sent: 6c 69 73 74 0d 0a\n", stderr.String())
 	})
 
 	// Positive test Interactive. Log is not used.
@@ -750,6 +2317,55 @@ func TestNewExecutor(t *testing.T) {
 	})
 }
 
+func TestColorCodes(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(func(c *rcontest.Context) {
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, c.Request().Body()).WriteTo(c.Conn())
+		}),
+	)
+	defer serverRCON.Close()
+
+	tests := []struct {
+		name     string
+		response string
+		colored  string
+		stripped string
+	}{
+		{"ascii", "§aonline", "\033[92monline" + colors.ANSIReset, "online"},
+		{"emoji", "🎮§aGame §c🔥", "🎮\033[92mGame \033[91m🔥" + colors.ANSIReset, "🎮Game 🔥"},
+		{"accents", "Joueur §6été§r à", "Joueur \033[33mété\033[0m à" + colors.ANSIReset, "Joueur été à"},
+		{"unknown code", "naïve §z", "naïve §z", "naïve z"},
+		{"trailing marker", "café§", "café§", "café"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			for _, noColor := range []bool{false, true} {
+				w := bytes.Buffer{}
+
+				app := executor.NewExecutor(nil, &w, "")
+
+				ses := &config.Session{Address: serverRCON.Addr(), Password: "password", NoColor: noColor}
+
+				err := app.Execute(&w, ses, tt.response)
+				assert.NoError(t, err)
+
+				expected := tt.colored
+				if noColor {
+					expected = tt.stripped
+				}
+
+				assert.Equal(t, expected+"\n", w.String())
+
+				app.Close()
+			}
+		})
+	}
+}
+
 // getVar returns environment variable or default value.
 func getVar(key string, fallback string) string {
 	if value := os.Getenv(key); value != "" {
@@ -0,0 +1,71 @@
+package executor
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+)
+
+// ErrEmptyAddressFile is returned when address file has no addresses.
+var ErrEmptyAddressFile = errors.New("address file has no addresses")
+
+// ReadAddressFile reads host:port lines from the file. Blank lines and lines
+// starting with # are skipped.
+func ReadAddressFile(name string) ([]string, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("address file: %w", err)
+	}
+	defer file.Close()
+
+	var addresses []string
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, CommandFileComment) {
+			continue
+		}
+
+		addresses = append(addresses, line)
+	}
+
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("address file: %w", err)
+	}
+
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrEmptyAddressFile, name)
+	}
+
+	return addresses, nil
+}
+
+// dialFailover tries session addresses in order until one authenticates.
+// The session address is set to the used endpoint.
+func (executor *Executor) dialFailover(ses *config.Session) error {
+	errs := make([]error, 0, len(ses.Addresses))
+
+	for _, address := range ses.Addresses {
+		if executor.halted() {
+			errs = append(errs, ErrHaltTimeout)
+
+			break
+		}
+
+		ses.Address = address
+
+		err := executor.dial(ses)
+		if err == nil {
+			return nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", address, err))
+	}
+
+	return errors.Join(errs...)
+}
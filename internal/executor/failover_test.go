@@ -0,0 +1,78 @@
+package executor_test
+
+import (
+	"bytes"
+	"net"
+	"os"
+	"testing"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/gorcon/rcon/rcontest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadAddressFile(t *testing.T) {
+	name := "addresses.txt"
+	defer os.Remove(name)
+
+	createFile(name, "# primary\n127.0.0.1:16260\n\n  127.0.0.1:16261  \n")
+
+	addresses, err := executor.ReadAddressFile(name)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"127.0.0.1:16260", "127.0.0.1:16261"}, addresses)
+
+	createFile(name, "# empty\n")
+
+	_, err = executor.ReadAddressFile(name)
+	assert.ErrorIs(t, err, executor.ErrEmptyAddressFile)
+
+	_, err = executor.ReadAddressFile("not-exist.txt")
+	assert.Error(t, err)
+}
+
+func TestDial_Failover(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	// Reserve the address of the down primary endpoint.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	down := listener.Addr().String()
+	listener.Close()
+
+	// Test down primary fails over to the backup.
+	t.Run("backup", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Addresses: []string{down, serverRCON.Addr()}, Password: "password"}
+
+		err := app.Execute(&w, ses, "help")
+		assert.NoError(t, err)
+		assert.Equal(t, serverRCON.Addr(), ses.Address)
+		assert.Equal(t, "Can I help you?\n", w.String())
+	})
+
+	// Test all endpoints are down.
+	t.Run("all down", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Addresses: []string{down, down}, Password: "password"}
+
+		err := app.Execute(&w, ses, "help")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), down+": auth:")
+	})
+}
@@ -0,0 +1,86 @@
+package executor
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+// ErrNotFIFO is returned when the command FIFO is not a named pipe.
+var ErrNotFIFO = errors.New("not a named pipe: create it with mkfifo")
+
+// CommandFIFO executes commands written to the named pipe line by line on
+// the persistent connection until ctx is done. Lines are parsed like command
+// file lines. Failed command is printed and the connection is dropped, so it
+// is dialed again for the next command.
+func (executor *Executor) CommandFIFO(ctx context.Context, w io.Writer, ses *config.Session, name string) error {
+	info, err := os.Stat(name)
+	if err != nil {
+		return fmt.Errorf("fifo: %w", err)
+	}
+
+	if info.Mode()&os.ModeNamedPipe == 0 {
+		return fmt.Errorf("fifo: %s: %w", name, ErrNotFIFO)
+	}
+
+	// The pipe is opened for writing too, so open doesn't block until
+	// the first writer and reading doesn't stop when writers close it.
+	file, err := os.OpenFile(name, os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("fifo: %w", err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = file.Close()
+	}()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if err = executor.ExecuteCommands(w, ses, ParseCommand(line)); err != nil {
+			_, _ = fmt.Fprintln(w, err)
+
+			executor.dropClient()
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	if err = scanner.Err(); err != nil {
+		return fmt.Errorf("fifo: %w", err)
+	}
+
+	return nil
+}
+
+// commandFIFO executes commands from the named pipe until SIGINT or SIGTERM.
+func (executor *Executor) commandFIFO(c *cli.Context, ses *config.Session, name string) error {
+	if ses.Address == "" {
+		return ErrEmptyAddress
+	}
+
+	if ses.Password == "" && !ses.NoAuth {
+		return ErrEmptyPassword
+	}
+
+	ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return executor.CommandFIFO(ctx, executor.responseWriter(ses), ses, name)
+}
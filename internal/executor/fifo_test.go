@@ -0,0 +1,84 @@
+//go:build !windows
+
+package executor_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/gorcon/rcon/rcontest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecutor_CommandFIFO(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	ses := &config.Session{Address: serverRCON.Addr(), Password: "password"}
+
+	t.Run("not fifo", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), "cmds")
+		if err := createFile(name, "help\n"); err != nil {
+			t.Fatal(err)
+		}
+
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		err := app.CommandFIFO(context.Background(), &bytes.Buffer{}, ses, name)
+		assert.ErrorIs(t, err, executor.ErrNotFIFO)
+	})
+
+	// Test commands from successive writers are executed until cancel.
+	t.Run("success", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), "cmds")
+		if err := syscall.Mkfifo(name, 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		w := &syncBuffer{}
+
+		app := executor.NewExecutor(nil, w, "")
+		defer app.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		done := make(chan error, 1)
+
+		go func() {
+			done <- app.CommandFIFO(ctx, w, ses, name)
+		}()
+
+		write := func(commands string) {
+			file, err := os.OpenFile(name, os.O_WRONLY, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, _ = file.WriteString(commands)
+			_ = file.Close()
+		}
+
+		write("# comment\nhelp\n\n")
+		assert.Eventually(t, func() bool { return w.String() == "Can I help you?\n" },
+			time.Second, 10*time.Millisecond)
+
+		write("unknown\n")
+		assert.Eventually(t, func() bool {
+			return w.String() == "Can I help you?\nunknown command\n"
+		}, time.Second, 10*time.Millisecond)
+
+		cancel()
+		assert.NoError(t, <-done)
+	})
+}
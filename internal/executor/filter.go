@@ -0,0 +1,75 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+)
+
+var (
+	// ErrCommandDenied is returned when the command is blocked by deny
+	// patterns or is not permitted by allow patterns.
+	ErrCommandDenied = errors.New("command denied")
+
+	// ErrInvalidPattern is returned when deny or allow pattern is not
+	// a valid regular expression.
+	ErrInvalidPattern = errors.New("invalid pattern")
+)
+
+// ValidatePatterns returns an error if any of deny or allow patterns is not
+// a valid regular expression.
+func ValidatePatterns(ses *config.Session) error {
+	_, err := compilePatterns(append(append([]string{}, ses.DenyPatterns...), ses.AllowOnly...))
+
+	return err
+}
+
+// checkCommand returns ErrCommandDenied if the command matches any of deny
+// patterns or allow patterns are set and the command matches none of them.
+func checkCommand(ses *config.Session, command string) error {
+	deny, err := compilePatterns(ses.DenyPatterns)
+	if err != nil {
+		return err
+	}
+
+	for _, re := range deny {
+		if re.MatchString(command) {
+			return fmt.Errorf("%w: %q matches deny pattern %q", ErrCommandDenied, command, re)
+		}
+	}
+
+	if len(ses.AllowOnly) == 0 {
+		return nil
+	}
+
+	allow, err := compilePatterns(ses.AllowOnly)
+	if err != nil {
+		return err
+	}
+
+	for _, re := range allow {
+		if re.MatchString(command) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %q does not match allowed patterns", ErrCommandDenied, command)
+}
+
+// compilePatterns compiles regular expressions.
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(patterns))
+
+	for _, pattern := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %w", ErrInvalidPattern, pattern, err)
+		}
+
+		res = append(res, re)
+	}
+
+	return res, nil
+}
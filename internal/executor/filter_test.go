@@ -0,0 +1,58 @@
+package executor_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/gorcon/rcon/rcontest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecute_Filter(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	// Test command matching deny pattern is refused.
+	t.Run("deny pattern", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", DenyPatterns: []string{`^(stop|ban)\b`}}
+
+		err := app.Execute(&w, ses, "stop")
+		assert.ErrorIs(t, err, executor.ErrCommandDenied)
+		assert.Empty(t, w.String())
+
+		err = app.Execute(&w, ses, "help")
+		assert.NoError(t, err)
+	})
+
+	// Test only allowed commands are sent.
+	t.Run("allow only", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", AllowOnly: []string{`^help$`}}
+
+		err := app.Execute(&w, ses, "help")
+		assert.NoError(t, err)
+
+		err = app.Execute(&w, ses, "list")
+		assert.ErrorIs(t, err, executor.ErrCommandDenied)
+	})
+
+	// Test invalid pattern.
+	t.Run("invalid pattern", func(t *testing.T) {
+		err := executor.ValidatePatterns(&config.Session{DenyPatterns: []string{"("}})
+		assert.ErrorIs(t, err, executor.ErrInvalidPattern)
+	})
+}
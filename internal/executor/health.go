@@ -0,0 +1,157 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+// Health probe defaults.
+const (
+	DefaultHealthAddr     = ":8080"
+	DefaultHealthInterval = 10 * time.Second
+
+	// HealthPath is the path of the health probe endpoint.
+	HealthPath = "/healthz"
+)
+
+// health keeps the result of the last ping.
+type health struct {
+	mu  sync.Mutex
+	err error
+}
+
+// set stores the result of the ping.
+func (h *health) set(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.err = err
+}
+
+// ServeHTTP responds 200 if the last ping succeeded and 503 with the ping
+// error otherwise.
+func (h *health) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	h.mu.Lock()
+	err := h.err
+	h.mu.Unlock()
+
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+
+		return
+	}
+
+	_, _ = fmt.Fprintln(w, "OK")
+}
+
+// ServeHealth pings the remote server every interval and serves HealthPath
+// on the listener until ctx is done. Each ping dials and authenticates over
+// a new connection, so a dropped server is noticed.
+func (executor *Executor) ServeHealth(ctx context.Context, listener net.Listener, ses *config.Session,
+	interval time.Duration,
+) error {
+	if interval <= 0 {
+		interval = DefaultHealthInterval
+	}
+
+	state := &health{}
+	ping := func() {
+		executor.dropClient()
+		state.set(executor.Dial(ses))
+		executor.dropClient()
+	}
+
+	ping()
+
+	mux := http.NewServeMux()
+	mux.Handle(HealthPath, state)
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: interval}
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- server.Serve(listener)
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			_ = server.Close()
+			<-done
+
+			return nil
+		case err := <-done:
+			if errors.Is(err, http.ErrServerClosed) {
+				return nil
+			}
+
+			return fmt.Errorf("health: %w", err)
+		case <-ticker.C:
+			ping()
+		}
+	}
+}
+
+// serveHealthCommand creates the serve-health subcommand.
+func (executor *Executor) serveHealthCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "serve-health",
+		Usage:     "Ping the server every interval and serve " + HealthPath + " for liveness probes",
+		UsageText: "serve-health --addr :8080 --interval 10s",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "addr",
+				Usage: "Address of the HTTP server",
+				Value: DefaultHealthAddr,
+			},
+			&cli.DurationFlag{
+				Name:  "interval",
+				Usage: "Interval between pings",
+				Value: DefaultHealthInterval,
+			},
+		},
+		Action: executor.serveHealth,
+	}
+}
+
+// serveHealth executes the serve-health subcommand until SIGINT or SIGTERM
+// sent by the container runtime.
+func (executor *Executor) serveHealth(c *cli.Context) error {
+	ses, err := executor.NewSession(c)
+	if err != nil {
+		return err
+	}
+
+	if ses.Address == "" {
+		return ErrEmptyAddress
+	}
+
+	if ses.Password == "" && !ses.NoAuth {
+		return ErrEmptyPassword
+	}
+
+	listener, err := net.Listen("tcp", c.String("addr"))
+	if err != nil {
+		return fmt.Errorf("health: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return executor.ServeHealth(ctx, listener, ses, c.Duration("interval"))
+}
@@ -0,0 +1,59 @@
+package executor_test
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/gorcon/rcon/rcontest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecutor_ServeHealth(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+	defer app.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+
+	go func() {
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password"}
+		done <- app.ServeHealth(ctx, listener, ses, 50*time.Millisecond)
+	}()
+
+	status := func() int {
+		resp, err := http.Get("http://" + listener.Addr().String() + executor.HealthPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		return resp.StatusCode
+	}
+
+	// Test reachable server is healthy.
+	assert.Eventually(t, func() bool { return status() == http.StatusOK }, time.Second, 10*time.Millisecond)
+
+	// Test the probe fails after the server is down.
+	serverRCON.Close()
+	assert.Eventually(t, func() bool { return status() == http.StatusServiceUnavailable }, time.Second, 10*time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-done)
+}
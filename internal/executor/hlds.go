@@ -0,0 +1,187 @@
+package executor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gorcon/rcon"
+)
+
+// HLDSPacketGap is the time to wait for the next packet of the HLDS response.
+// Long responses are split into several datagrams without a terminator.
+const HLDSPacketGap = 100 * time.Millisecond
+
+var (
+	// ErrHLDSChallenge is returned when the server doesn't answer with
+	// the RCON challenge.
+	ErrHLDSChallenge = errors.New("invalid challenge response")
+
+	// ErrUDPTunnel is returned when the UDP protocol is routed through
+	// proxy or SSH which tunnel only TCP connections.
+	ErrUDPTunnel = errors.New("hlds protocol over UDP can't be tunneled through proxy or ssh")
+)
+
+// hldsHeader prefixes every connectionless GoldSrc packet.
+var hldsHeader = []byte{0xff, 0xff, 0xff, 0xff}
+
+// HLDS response markers.
+const (
+	hldsChallengePrefix = "challenge rcon "
+	hldsPrintMarker     = 'l'
+	hldsBadPassword     = "Bad rcon_password"
+	hldsBadChallenge    = "Bad challenge"
+)
+
+// hldsConn is a GoldSrc (HLDS) RCON connection. Unlike Source RCON it runs
+// over UDP without authentication request: the client gets the challenge
+// number and sends it with the password in every command.
+type hldsConn struct {
+	conn      net.Conn
+	password  string
+	deadline  time.Duration
+	challenge string
+}
+
+// dialHLDS creates a new HLDS RCON connection and requests the challenge.
+// The password is checked by the server with the first command.
+func dialHLDS(address string, password string, dialTimeout time.Duration, deadline time.Duration) (*hldsConn, error) {
+	conn, err := net.DialTimeout("udp", address, dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("hlds: %w", err)
+	}
+
+	client := hldsConn{conn: conn, password: password, deadline: deadline}
+
+	if err = client.requestChallenge(); err != nil {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("hlds: %w", err)
+	}
+
+	return &client, nil
+}
+
+// Execute sends the command with the challenge and collects the response
+// packets. The challenge is requested again once if the server rejects it.
+func (c *hldsConn) Execute(command string) (string, error) {
+	if command == "" {
+		return "", rcon.ErrCommandEmpty
+	}
+
+	response, err := c.execute(command)
+	if err == nil && strings.HasPrefix(response, hldsBadChallenge) {
+		if err = c.requestChallenge(); err != nil {
+			return "", fmt.Errorf("hlds: %w", err)
+		}
+
+		response, err = c.execute(command)
+	}
+
+	if err != nil {
+		return response, fmt.Errorf("hlds: %w", err)
+	}
+
+	if strings.HasPrefix(response, hldsBadPassword) {
+		return "", fmt.Errorf("hlds: %w", rcon.ErrAuthFailed)
+	}
+
+	return response, nil
+}
+
+// Close closes the connection.
+func (c *hldsConn) Close() error {
+	return c.conn.Close()
+}
+
+// execute sends the rcon packet and reads the response.
+func (c *hldsConn) execute(command string) (string, error) {
+	if err := c.write(fmt.Sprintf("rcon %s \"%s\" %s\n", c.challenge, c.password, command)); err != nil {
+		return "", err
+	}
+
+	return c.readResponse()
+}
+
+// requestChallenge gets the challenge number used in commands.
+func (c *hldsConn) requestChallenge() error {
+	if err := c.write("challenge rcon\n"); err != nil {
+		return err
+	}
+
+	body, err := c.read(c.deadline)
+	if err != nil {
+		return err
+	}
+
+	challenge, ok := strings.CutPrefix(strings.TrimSpace(body), hldsChallengePrefix)
+	if !ok || challenge == "" {
+		return fmt.Errorf("%w: %q", ErrHLDSChallenge, body)
+	}
+
+	c.challenge = challenge
+
+	return nil
+}
+
+// readResponse reads the first packet of the response and the following
+// packets arriving within HLDSPacketGap.
+func (c *hldsConn) readResponse() (string, error) {
+	body, err := c.read(c.deadline)
+	if err != nil {
+		return "", err
+	}
+
+	var response strings.Builder
+	response.WriteString(strings.TrimPrefix(body, string(hldsPrintMarker)))
+
+	for {
+		body, err = c.read(HLDSPacketGap)
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			return response.String(), nil
+		}
+
+		if err != nil {
+			return response.String(), err
+		}
+
+		response.WriteString(strings.TrimPrefix(body, string(hldsPrintMarker)))
+	}
+}
+
+// write sends the connectionless packet with the payload.
+func (c *hldsConn) write(payload string) error {
+	if c.deadline != 0 {
+		_ = c.conn.SetWriteDeadline(time.Now().Add(c.deadline))
+	}
+
+	_, err := c.conn.Write(append(append([]byte{}, hldsHeader...), payload...))
+
+	return err
+}
+
+// read reads the packet and returns its payload without the header and
+// the trailing zero bytes.
+func (c *hldsConn) read(timeout time.Duration) (string, error) {
+	if timeout != 0 {
+		_ = c.conn.SetReadDeadline(time.Now().Add(timeout))
+	}
+
+	// Maximum size of the GoldSrc packet.
+	const size = 1400
+
+	buffer := make([]byte, size)
+
+	n, err := c.conn.Read(buffer)
+	if err != nil {
+		return "", err
+	}
+
+	packet := bytes.TrimPrefix(buffer[:n], hldsHeader)
+
+	return string(bytes.TrimRight(packet, "\x00")), nil
+}
@@ -0,0 +1,112 @@
+package executor_test
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/gorcon/rcon"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockHLDSChallenge is the challenge number issued by the mock HLDS server.
+const MockHLDSChallenge = "1234567"
+
+// newHLDSServer starts GoldSrc RCON server over UDP. The status command
+// response is split into two packets.
+func newHLDSServer(t *testing.T, password string) string {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	header := "\xff\xff\xff\xff"
+
+	go func() {
+		buffer := make([]byte, 1400)
+
+		for {
+			n, addr, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+
+			request := strings.TrimSuffix(strings.TrimPrefix(string(buffer[:n]), header), "\n")
+
+			reply := func(body string) {
+				_, _ = conn.WriteTo([]byte(header+body+"\x00"), addr)
+			}
+
+			if request == "challenge rcon" {
+				reply("challenge rcon " + MockHLDSChallenge + "\n")
+
+				continue
+			}
+
+			fields := strings.SplitN(request, " ", 4)
+			switch {
+			case len(fields) != 4 || fields[0] != "rcon" || fields[1] != MockHLDSChallenge:
+				reply("lBad challenge.\n")
+			case fields[2] != `"`+password+`"`:
+				reply("lBad rcon_password.\n")
+			case fields[3] == "status":
+				reply("lhostname: Half-Life\n")
+				reply("lplayers : 0 active\n")
+			default:
+				reply("lUnknown command \"" + fields[3] + "\"\n")
+			}
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func TestExecute_HLDS(t *testing.T) {
+	addr := newHLDSServer(t, "password")
+
+	// Test split response is collected.
+	t.Run("status", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: addr, Password: "password", Type: config.ProtocolHLDS}
+
+		err := app.Execute(&w, ses, "status", "help")
+		assert.NoError(t, err)
+		assert.Equal(t, "hostname: Half-Life\nplayers : 0 active\n--------\nUnknown command \"help\"\n", w.String())
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: addr, Password: "wrong", Type: config.ProtocolHLDS}
+
+		err := app.Execute(&w, ses, "status")
+		assert.ErrorIs(t, err, rcon.ErrAuthFailed)
+	})
+
+	// Test UDP protocol is not routed through TCP tunnels.
+	t.Run("proxy", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: addr, Password: "password", Type: config.ProtocolHLDS, Proxy: "socks5://127.0.0.1:1080"}
+
+		err := app.Execute(&w, ses, "status")
+		assert.ErrorIs(t, err, executor.ErrUDPTunnel)
+	})
+}
@@ -0,0 +1,103 @@
+package executor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Supported input formats of command lists.
+const (
+	InputFormatText = "text"
+	InputFormatJSON = "json"
+)
+
+var (
+	// ErrUnsupportedInputFormat is returned when input format is not one
+	// of the supported formats.
+	ErrUnsupportedInputFormat = errors.New("unsupported input format")
+
+	// ErrInvalidJSONCommand is returned when JSON command list element is
+	// neither a string nor a command object.
+	ErrInvalidJSONCommand = errors.New("invalid json command")
+)
+
+// jsonCommand is the command object in JSON input format.
+type jsonCommand struct {
+	Command string `json:"command"`
+	Timeout string `json:"timeout"`
+	Output  string `json:"output"`
+	Append  bool   `json:"append"`
+}
+
+// ValidateInputFormat returns an error if input format is not supported.
+// Empty format is treated as text.
+func ValidateInputFormat(format string) error {
+	switch format {
+	case "", InputFormatText, InputFormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("%w %q: allowed %q and %q", ErrUnsupportedInputFormat, format, InputFormatText, InputFormatJSON)
+	}
+}
+
+// ReadCommandsFormat reads commands from r in the input format.
+func ReadCommandsFormat(r io.Reader, format string) ([]Command, error) {
+	if format == InputFormatJSON {
+		return ReadJSONCommands(r)
+	}
+
+	return ReadCommands(r)
+}
+
+// ReadJSONCommands reads commands from JSON array. Elements of the array
+// are command strings or objects with command and its options:
+//
+//	["save-all", {"command": "kick X", "timeout": "5s"}]
+func ReadJSONCommands(r io.Reader) ([]Command, error) {
+	var elements []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&elements); err != nil {
+		return nil, fmt.Errorf("read commands: %w", err)
+	}
+
+	commands := make([]Command, 0, len(elements))
+
+	for i, element := range elements {
+		command, err := parseJSONCommand(element)
+		if err != nil {
+			return nil, fmt.Errorf("read commands: element %d: %w", i, err)
+		}
+
+		commands = append(commands, command)
+	}
+
+	return commands, nil
+}
+
+// parseJSONCommand parses element of JSON command list.
+func parseJSONCommand(element json.RawMessage) (Command, error) {
+	var text string
+	if err := json.Unmarshal(element, &text); err == nil {
+		return Command{Text: text}, nil
+	}
+
+	var object jsonCommand
+	if err := json.Unmarshal(element, &object); err != nil {
+		return Command{}, fmt.Errorf("%w: %s", ErrInvalidJSONCommand, element)
+	}
+
+	command := Command{Text: object.Command, Output: object.Output, Append: object.Append}
+
+	if object.Timeout != "" {
+		timeout, err := time.ParseDuration(object.Timeout)
+		if err != nil {
+			return Command{}, fmt.Errorf("%w: timeout: %w", ErrInvalidJSONCommand, err)
+		}
+
+		command.Timeout = timeout
+	}
+
+	return command, nil
+}
@@ -0,0 +1,78 @@
+package executor_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/gorcon/rcon/rcontest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadJSONCommands(t *testing.T) {
+	t.Run("strings and objects", func(t *testing.T) {
+		r := strings.NewReader(`["save-all", {"command": "kick X", "timeout": "5s"}, {"command": "list", "output": "players.txt"}]`)
+
+		commands, err := executor.ReadJSONCommands(r)
+		assert.NoError(t, err)
+		assert.Equal(t, []executor.Command{
+			{Text: "save-all"},
+			{Text: "kick X", Timeout: 5 * time.Second},
+			{Text: "list", Output: "players.txt"},
+		}, commands)
+	})
+
+	t.Run("invalid element", func(t *testing.T) {
+		_, err := executor.ReadJSONCommands(strings.NewReader(`["list", 42]`))
+		assert.ErrorIs(t, err, executor.ErrInvalidJSONCommand)
+	})
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		_, err := executor.ReadJSONCommands(strings.NewReader(`[{"command": "list", "timeout": "soon"}]`))
+		assert.ErrorIs(t, err, executor.ErrInvalidJSONCommand)
+	})
+
+	t.Run("not array", func(t *testing.T) {
+		_, err := executor.ReadJSONCommands(strings.NewReader(`"list"`))
+		assert.Error(t, err)
+	})
+}
+
+func TestExecutor_InputFormat(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	// JSON command list is read from stdin when no commands are given.
+	t.Run("json from stdin", func(t *testing.T) {
+		r := strings.NewReader(`["help", {"command": "sleep", "timeout": "100ms"}, "help"]`)
+		w := &bytes.Buffer{}
+
+		app := executor.NewExecutor(r, w, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password", "-s", "--input-format=json")
+
+		err := app.Run(args)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, strings.Count(w.String(), "Can I help you?"))
+		assert.Contains(t, w.String(), "i/o timeout")
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		app := executor.NewExecutor(nil, &bytes.Buffer{}, "")
+		defer app.Close()
+
+		args := os.Args[0:1]
+		args = append(args, "-a="+serverRCON.Addr(), "-p=password", "--input-format=xml", "help")
+
+		err := app.Run(args)
+		assert.ErrorIs(t, err, executor.ErrUnsupportedInputFormat)
+	})
+}
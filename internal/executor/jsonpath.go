@@ -0,0 +1,199 @@
+package executor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+)
+
+var (
+	// ErrInvalidJSONPath is returned when JSON path can't be parsed.
+	ErrInvalidJSONPath = errors.New("invalid JSON path")
+
+	// ErrInvalidJSONResponse is returned when JSON path is set and
+	// the response is not valid JSON.
+	ErrInvalidJSONResponse = errors.New("response is not valid JSON")
+)
+
+// jsonPathStep is the step of the JSON path: object field, array index or
+// wildcard matching all elements.
+type jsonPathStep struct {
+	field    string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// ValidateJSONPath returns an error if the session JSON path can't be
+// parsed.
+func ValidateJSONPath(ses *config.Session) error {
+	if ses.JSONPath == "" {
+		return nil
+	}
+
+	_, err := parseJSONPath(ses.JSONPath)
+
+	return err
+}
+
+// parseJSONPath parses the path like `.players[*].name`. The leading `$`
+// is optional. Fields are separated with dots or set in brackets with
+// quotes, indexes and `*` wildcard are set in brackets.
+func parseJSONPath(path string) ([]jsonPathStep, error) {
+	var steps []jsonPathStep
+
+	rest := strings.TrimPrefix(path, "$")
+
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+
+			name := rest[:end]
+			rest = rest[end:]
+
+			switch {
+			case name == "*":
+				steps = append(steps, jsonPathStep{wildcard: true})
+			case name != "":
+				steps = append(steps, jsonPathStep{field: name})
+			case rest == "" || rest[0] != '[':
+				return nil, fmt.Errorf("%w %q: empty field name", ErrInvalidJSONPath, path)
+			}
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("%w %q: unclosed bracket", ErrInvalidJSONPath, path)
+			}
+
+			step, err := parseJSONPathBracket(rest[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("%w %q: %w", ErrInvalidJSONPath, path, err)
+			}
+
+			steps = append(steps, step)
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("%w %q: expected . or [", ErrInvalidJSONPath, path)
+		}
+	}
+
+	return steps, nil
+}
+
+// parseJSONPathBracket parses the content of brackets: `*`, index or
+// quoted field name.
+func parseJSONPathBracket(inner string) (jsonPathStep, error) {
+	inner = strings.TrimSpace(inner)
+
+	if inner == "*" {
+		return jsonPathStep{wildcard: true}, nil
+	}
+
+	if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+		return jsonPathStep{field: inner[1 : len(inner)-1]}, nil
+	}
+
+	index, err := strconv.Atoi(inner)
+	if err != nil || index < 0 {
+		return jsonPathStep{}, fmt.Errorf("invalid index %q", inner)
+	}
+
+	return jsonPathStep{index: index, isIndex: true}, nil
+}
+
+// ExtractJSONPath returns values of the JSON response matching the path,
+// one per line. Strings are printed without quotes, other values as JSON.
+func ExtractJSONPath(path string, response string) (string, error) {
+	steps, err := parseJSONPath(path)
+	if err != nil {
+		return "", err
+	}
+
+	dec := json.NewDecoder(strings.NewReader(response))
+	dec.UseNumber()
+
+	var root interface{}
+	if err = dec.Decode(&root); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidJSONResponse, err)
+	}
+
+	values := []interface{}{root}
+	for _, step := range steps {
+		values = step.apply(values)
+	}
+
+	lines := make([]string, 0, len(values))
+
+	for _, value := range values {
+		switch v := value.(type) {
+		case string:
+			lines = append(lines, v)
+		case json.Number:
+			lines = append(lines, v.String())
+		default:
+			js, err := json.Marshal(v)
+			if err != nil {
+				return "", fmt.Errorf("marshal: %w", err)
+			}
+
+			lines = append(lines, string(js))
+		}
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// apply returns the values selected by the step. Missing fields and
+// indexes are skipped.
+func (step jsonPathStep) apply(values []interface{}) []interface{} {
+	var selected []interface{}
+
+	for _, value := range values {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if step.wildcard {
+				keys := make([]string, 0, len(v))
+				for key := range v {
+					keys = append(keys, key)
+				}
+
+				sort.Strings(keys)
+
+				for _, key := range keys {
+					selected = append(selected, v[key])
+				}
+			} else if field, ok := v[step.field]; ok && !step.isIndex {
+				selected = append(selected, field)
+			}
+		case []interface{}:
+			switch {
+			case step.wildcard:
+				selected = append(selected, v...)
+			case step.isIndex && step.index < len(v):
+				selected = append(selected, v[step.index])
+			}
+		}
+	}
+
+	return selected
+}
+
+// extractJSONPath applies the session JSON path to the response.
+func extractJSONPath(ses *config.Session, response string) (string, error) {
+	if ses.JSONPath == "" {
+		return response, nil
+	}
+
+	return ExtractJSONPath(ses.JSONPath, response)
+}
@@ -0,0 +1,77 @@
+package executor_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/gorcon/rcon"
+	"github.com/gorcon/rcon/rcontest"
+	"github.com/stretchr/testify/assert"
+)
+
+const MockPlayersJSON = `{"count": 2, "players": [{"name": "Steve", "ping": 31}, {"name": "Alex", "ping": 45.5}], "map": {"name": "Procedural Map"}}`
+
+func TestExtractJSONPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+		err      error
+	}{
+		{"field", ".count", "2", nil},
+		{"nested field", ".map.name", "Procedural Map", nil},
+		{"wildcard", ".players[*].name", "Steve\nAlex", nil},
+		{"index", "$.players[1].ping", "45.5", nil},
+		{"quoted field", `.players[0]["name"]`, "Steve", nil},
+		{"object", ".players[0]", `{"name":"Steve","ping":31}`, nil},
+		{"missing", ".players[5].name", "", nil},
+		{"invalid path", "players", "", executor.ErrInvalidJSONPath},
+		{"unclosed bracket", ".players[0", "", executor.ErrInvalidJSONPath},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := executor.ExtractJSONPath(tt.path, MockPlayersJSON)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, values)
+		})
+	}
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, err := executor.ExtractJSONPath(".count", "Unknown command")
+		assert.ErrorIs(t, err, executor.ErrInvalidJSONResponse)
+	})
+}
+
+func TestExecute_JSONPath(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(func(c *rcontest.Context) {
+			responseBody := MockPlayersJSON
+			if c.Request().Body() != "playerlist" {
+				responseBody = "Unknown command"
+			}
+
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, responseBody).WriteTo(c.Conn())
+		}),
+	)
+	defer serverRCON.Close()
+
+	w := bytes.Buffer{}
+
+	app := executor.NewExecutor(nil, &w, "")
+	defer app.Close()
+
+	ses := &config.Session{Address: serverRCON.Addr(), Password: "password", JSONPath: ".players[*].name"}
+
+	err := app.Execute(&w, ses, "playerlist")
+	assert.NoError(t, err)
+	assert.Equal(t, "Steve\nAlex\n", w.String())
+
+	err = app.Execute(&w, ses, "status")
+	assert.ErrorIs(t, err, executor.ErrInvalidJSONResponse)
+}
@@ -0,0 +1,71 @@
+package executor
+
+import (
+	"bufio"
+	"io"
+	"time"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+)
+
+// DefaultKeepaliveCommand is the harmless command sent by interactive
+// keepalive.
+const DefaultKeepaliveCommand = "echo"
+
+// readLines reads lines from r in the background so that interactive mode
+// can wait for input and keepalive timer at the same time. The channel is
+// closed when r is exhausted.
+func readLines(r io.Reader) <-chan string {
+	lines := make(chan string)
+
+	go func() {
+		defer close(lines)
+
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	return lines
+}
+
+// nextLine waits for the next input line. While waiting it sends keepalive
+// command every InteractiveKeepalive period of idleness. Returns false when
+// the input is exhausted.
+func (executor *Executor) nextLine(ses *config.Session, lines <-chan string) (string, bool) {
+	if ses.InteractiveKeepalive <= 0 {
+		line, ok := <-lines
+
+		return line, ok
+	}
+
+	timer := time.NewTimer(ses.InteractiveKeepalive)
+	defer timer.Stop()
+
+	for {
+		select {
+		case line, ok := <-lines:
+			return line, ok
+		case <-timer.C:
+			executor.keepalive(ses)
+			timer.Reset(ses.InteractiveKeepalive)
+		}
+	}
+}
+
+// keepalive sends keepalive command and discards the response. Errors are
+// ignored as the dropped connection is reported by the next command.
+func (executor *Executor) keepalive(ses *config.Session) {
+	_, _ = executor.request(ses, keepaliveCommand(ses))
+}
+
+// keepaliveCommand returns the keepalive command of the session or
+// the default one.
+func keepaliveCommand(ses *config.Session) string {
+	if ses.KeepaliveCommand != "" {
+		return ses.KeepaliveCommand
+	}
+
+	return DefaultKeepaliveCommand
+}
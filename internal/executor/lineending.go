@@ -0,0 +1,63 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+)
+
+// Supported command line endings.
+const (
+	LineEndingLF   = "lf"
+	LineEndingCRLF = "crlf"
+	LineEndingCR   = "cr"
+)
+
+// ErrUnsupportedLineEnding is returned when line ending is not one of the
+// supported values or can't be used with the protocol.
+var ErrUnsupportedLineEnding = errors.New("unsupported line ending")
+
+// lineEndings maps line ending names to terminators.
+var lineEndings = map[string]string{
+	LineEndingLF:   "\n",
+	LineEndingCRLF: "\r\n",
+	LineEndingCR:   "\r",
+}
+
+// ValidateLineEnding returns an error if line ending is not supported.
+// Empty value keeps commands as is. The TELNET library always terminates
+// commands with CRLF, so other line endings can't be used with it.
+func ValidateLineEnding(ses *config.Session) error {
+	if ses.LineEnding == "" {
+		return nil
+	}
+
+	if _, ok := lineEndings[ses.LineEnding]; !ok {
+		return fmt.Errorf("%w %q: allowed %q, %q and %q",
+			ErrUnsupportedLineEnding, ses.LineEnding, LineEndingLF, LineEndingCRLF, LineEndingCR)
+	}
+
+	if isTELNET(ses) && ses.LineEnding != LineEndingCRLF {
+		return fmt.Errorf("%w %q by %s protocol: commands are always terminated with %q",
+			ErrUnsupportedLineEnding, ses.LineEnding, ses.Type, LineEndingCRLF)
+	}
+
+	return nil
+}
+
+// terminate appends the session line ending to the command before sending.
+// TELNET commands are terminated by the library.
+func terminate(ses *config.Session, command string) string {
+	if isTELNET(ses) {
+		return command
+	}
+
+	return command + lineEndings[ses.LineEnding]
+}
+
+// isTELNET reports whether the session uses TELNET protocol with or without
+// TLS.
+func isTELNET(ses *config.Session) bool {
+	return ses.Type == config.ProtocolTELNET || ses.Type == config.ProtocolTELNETS
+}
@@ -0,0 +1,61 @@
+package executor_test
+
+import (
+	"bytes"
+	"strconv"
+	"testing"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/gorcon/rcon"
+	"github.com/gorcon/rcon/rcontest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateLineEnding(t *testing.T) {
+	assert.NoError(t, executor.ValidateLineEnding(&config.Session{}))
+	assert.NoError(t, executor.ValidateLineEnding(&config.Session{LineEnding: executor.LineEndingCR}))
+	assert.NoError(t, executor.ValidateLineEnding(&config.Session{LineEnding: executor.LineEndingCRLF, Type: config.ProtocolTELNET}))
+
+	err := executor.ValidateLineEnding(&config.Session{LineEnding: "nl"})
+	assert.ErrorIs(t, err, executor.ErrUnsupportedLineEnding)
+
+	err = executor.ValidateLineEnding(&config.Session{LineEnding: executor.LineEndingLF, Type: config.ProtocolTELNET})
+	assert.ErrorIs(t, err, executor.ErrUnsupportedLineEnding)
+}
+
+func TestExecute_LineEnding(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(func(c *rcontest.Context) {
+			// Echo the received command with visible line ending.
+			responseBody := strconv.Quote(c.Request().Body())
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, responseBody).WriteTo(c.Conn())
+		}),
+	)
+	defer serverRCON.Close()
+
+	tests := []struct {
+		lineEnding string
+		expected   string
+	}{
+		{"", `"list"`},
+		{executor.LineEndingLF, `"list\n"`},
+		{executor.LineEndingCRLF, `"list\r\n"`},
+		{executor.LineEndingCR, `"list\r"`},
+	}
+
+	for _, tt := range tests {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", LineEnding: tt.lineEnding}
+
+		err := app.Execute(&w, ses, "list")
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expected+"\n", w.String())
+
+		app.Close()
+	}
+}
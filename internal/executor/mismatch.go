@@ -0,0 +1,121 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/gorcon/rcon"
+	"github.com/gorcon/telnet"
+	"github.com/gorcon/websocket"
+	gorilla "github.com/gorilla/websocket"
+)
+
+// ProbeTimeout is the timeout for each step of the protocol probe.
+const ProbeTimeout = 500 * time.Millisecond
+
+// ErrProtocolMismatch is returned when the remote server looks like it
+// speaks a different protocol than the one specified by --type.
+var ErrProtocolMismatch = errors.New("protocol mismatch")
+
+// withProtocolHint probes the remote server after a failed dial and wraps
+// the error with a suggestion for the likely correct protocol type.
+// The original error is returned as is if no other protocol was detected.
+// HLDS servers listen on UDP and can't be probed over TCP.
+func withProtocolHint(ses *config.Session, err error) error {
+	if ses.Type == config.ProtocolHLDS || !isProtocolMismatchCandidate(err) {
+		return err
+	}
+
+	detected := probeProtocol(ses.Address)
+	if detected == "" {
+		return err
+	}
+
+	current := ses.Type
+	if current == "" {
+		current = config.DefaultProtocol
+	}
+
+	if detected == current {
+		return err
+	}
+
+	return fmt.Errorf("%w: %w: server looks like %q, try --type %s", err, ErrProtocolMismatch, detected, detected)
+}
+
+// isProtocolMismatchCandidate reports whether the dial error can be caused
+// by a protocol mismatch. Only errors of the connection that was accepted
+// and then broke the protocol are: unexpected auth responses, timeouts,
+// closed connections and malformed HTTP responses. Refused connections and
+// password errors are not.
+func isProtocolMismatchCandidate(err error) bool {
+	if errors.Is(err, rcon.ErrAuthFailed) || errors.Is(err, telnet.ErrAuthFailed) ||
+		errors.Is(err, websocket.ErrAuthFailed) || errors.Is(err, gorilla.ErrBadHandshake) {
+		return false
+	}
+
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return false
+	}
+
+	switch {
+	case errors.Is(err, rcon.ErrAuthNotRCON), errors.Is(err, rcon.ErrInvalidAuthResponse),
+		errors.Is(err, rcon.ErrInvalidPacketID), errors.Is(err, rcon.ErrInvalidPacketPadding),
+		errors.Is(err, rcon.ErrResponseTooSmall), errors.Is(err, telnet.ErrAuthUnexpectedMessage):
+		return true
+	case isTimeout(err), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, syscall.ECONNRESET):
+		return true
+	}
+
+	// The HTTP client of WebRCON returns unexported parse errors.
+	return strings.Contains(err.Error(), "malformed HTTP")
+}
+
+// probeProtocol opens a raw connection to the address and looks for
+// protocol signatures. TELNET servers greet with the password prompt,
+// WebRCON servers respond to a plain HTTP request.
+func probeProtocol(address string) string {
+	if banner := probe(address, ""); strings.Contains(banner, telnet.ResponseEnterPassword) {
+		return config.ProtocolTELNET
+	}
+
+	request := "GET / HTTP/1.1\r\nHost: " + address + "\r\nConnection: close\r\n\r\n"
+	if response := probe(address, request); strings.HasPrefix(response, "HTTP/") {
+		return config.ProtocolWebRCON
+	}
+
+	return ""
+}
+
+// probe sends the request to the address if it is not empty and returns
+// the first bytes of the response.
+func probe(address string, request string) string {
+	conn, err := net.DialTimeout("tcp", address, ProbeTimeout)
+	if err != nil {
+		return ""
+	}
+	defer conn.Close()
+
+	_ = conn.SetDeadline(time.Now().Add(ProbeTimeout))
+
+	if request != "" {
+		if _, err = conn.Write([]byte(request)); err != nil {
+			return ""
+		}
+	}
+
+	const size = 512
+
+	buffer := make([]byte, size)
+	n, _ := conn.Read(buffer)
+
+	return string(buffer[:n])
+}
@@ -0,0 +1,133 @@
+package executor
+
+import (
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/gorcon/rcon"
+)
+
+// multiPacketConn is a Source RCON connection which collects responses split
+// into several packets. The rcon library reads only the first packet of
+// the response, so long responses like cvarlist are truncated. After each
+// command an empty SERVERDATA_RESPONSE_VALUE packet is sent, the server
+// mirrors it after the last packet of the response.
+type multiPacketConn struct {
+	conn     net.Conn
+	deadline time.Duration
+	id       int32
+}
+
+// dialMultiPacket creates a new authorized multi packet RCON connection.
+func dialMultiPacket(address string, password string, dialTimeout time.Duration, deadline time.Duration,
+) (*multiPacketConn, error) {
+	conn, err := net.DialTimeout("tcp", address, dialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("rcon: %w", err)
+	}
+
+	client := multiPacketConn{conn: conn, deadline: deadline}
+
+	if err = client.auth(password); err != nil {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("rcon: %w", err)
+	}
+
+	return &client, nil
+}
+
+// Execute sends the command followed by the terminator packet and collects
+// response packets until the server mirrors the terminator.
+func (c *multiPacketConn) Execute(command string) (string, error) {
+	if command == "" {
+		return "", rcon.ErrCommandEmpty
+	}
+
+	if len(command) > rcon.MaxCommandLen {
+		return "", rcon.ErrCommandTooLong
+	}
+
+	c.setDeadline()
+
+	commandID, terminatorID := c.nextID(), c.nextID()
+
+	if _, err := rcon.NewPacket(rcon.SERVERDATA_EXECCOMMAND, commandID, command).WriteTo(c.conn); err != nil {
+		return "", fmt.Errorf("rcon: %w", err)
+	}
+
+	if _, err := rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, terminatorID, "").WriteTo(c.conn); err != nil {
+		return "", fmt.Errorf("rcon: %w", err)
+	}
+
+	var response strings.Builder
+
+	for {
+		var packet rcon.Packet
+		if _, err := packet.ReadFrom(c.conn); err != nil {
+			return response.String(), err
+		}
+
+		switch packet.ID {
+		case terminatorID:
+			// Servers send one more packet for the terminator, it is skipped
+			// by the next command because of the stale ID.
+			return response.String(), nil
+		case commandID:
+			response.WriteString(packet.Body())
+		}
+	}
+}
+
+// Close closes the connection.
+func (c *multiPacketConn) Close() error {
+	return c.conn.Close()
+}
+
+// auth sends SERVERDATA_AUTH request and waits for SERVERDATA_AUTH_RESPONSE
+// skipping the empty SERVERDATA_RESPONSE_VALUE packet sent before it.
+func (c *multiPacketConn) auth(password string) error {
+	c.setDeadline()
+
+	id := c.nextID()
+
+	if _, err := rcon.NewPacket(rcon.SERVERDATA_AUTH, id, password).WriteTo(c.conn); err != nil {
+		return err
+	}
+
+	for {
+		var packet rcon.Packet
+		if _, err := packet.ReadFrom(c.conn); err != nil {
+			return err
+		}
+
+		if packet.Type != rcon.SERVERDATA_AUTH_RESPONSE {
+			continue
+		}
+
+		switch packet.ID {
+		case -1:
+			return rcon.ErrAuthFailed
+		case id:
+			return nil
+		default:
+			return rcon.ErrInvalidPacketID
+		}
+	}
+}
+
+// setDeadline sets the deadline for the next request if it is configured.
+func (c *multiPacketConn) setDeadline() {
+	if c.deadline != 0 {
+		_ = c.conn.SetDeadline(time.Now().Add(c.deadline))
+	}
+}
+
+// nextID returns the next packet ID.
+func (c *multiPacketConn) nextID() int32 {
+	c.id++
+
+	return c.id
+}
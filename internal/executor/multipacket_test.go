@@ -0,0 +1,134 @@
+package executor_test
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/gorcon/rcon"
+	"github.com/stretchr/testify/assert"
+)
+
+// MockFragments is the response of cvarlist command split into packets.
+var MockFragments = []string{"sv_cheats 0\n", "sv_gravity 800\n", "mp_timelimit 30"}
+
+// serveFragmented runs a Source RCON server which splits responses into
+// several packets and mirrors the terminator packet like real servers do.
+func serveFragmented(t *testing.T) net.Listener {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go handleFragmented(conn)
+		}
+	}()
+
+	return listener
+}
+
+func handleFragmented(conn net.Conn) {
+	defer conn.Close()
+
+	for {
+		var request rcon.Packet
+		if _, err := request.ReadFrom(conn); err != nil {
+			return
+		}
+
+		switch request.Type {
+		case rcon.SERVERDATA_AUTH:
+			id := request.ID
+			if request.Body() != "password" {
+				id = -1
+			}
+
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, request.ID, "").WriteTo(conn)
+			rcon.NewPacket(rcon.SERVERDATA_AUTH_RESPONSE, id, "").WriteTo(conn)
+		case rcon.SERVERDATA_EXECCOMMAND:
+			for i, fragment := range MockFragments {
+				// Late packet of another request arrives in the middle.
+				if request.Body() == "interleaved" && i == 1 {
+					rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, request.ID-1, "stale").WriteTo(conn)
+				}
+
+				rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, request.ID, fragment).WriteTo(conn)
+			}
+		case rcon.SERVERDATA_RESPONSE_VALUE:
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, request.ID, "").WriteTo(conn)
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, request.ID, "\x00\x00\x00\x01").WriteTo(conn)
+		}
+	}
+}
+
+func TestExecute_MultiPacket(t *testing.T) {
+	listener := serveFragmented(t)
+	defer listener.Close()
+
+	full := strings.Join(MockFragments, "")
+
+	// Test the rcon library reads only the first packet of the response.
+	t.Run("truncated", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		err := app.Execute(&w, &config.Session{Address: listener.Addr().String(), Password: "password"}, "cvarlist")
+		assert.NoError(t, err)
+		assert.NotEqual(t, full+"\n", w.String())
+	})
+
+	// Test all packets are collected.
+	t.Run("multi packet", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: listener.Addr().String(), Password: "password", MultiPacket: true}
+
+		err := app.Execute(&w, ses, "cvarlist", "cvarlist")
+		assert.NoError(t, err)
+		assert.Equal(t, full+"\n"+executor.CommandsResponseSeparator+"\n"+full+"\n", w.String())
+	})
+
+	// Test packets of other requests are skipped by ID.
+	t.Run("out of order", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: listener.Addr().String(), Password: "password", MultiPacket: true}
+
+		err := app.Execute(&w, ses, "interleaved")
+		assert.NoError(t, err)
+		assert.Equal(t, full+"\n", w.String())
+	})
+
+	// Test wrong password.
+	t.Run("wrong password", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: listener.Addr().String(), Password: "wrong", MultiPacket: true}
+
+		err := app.Execute(&w, ses, "cvarlist")
+		assert.ErrorIs(t, err, rcon.ErrAuthFailed)
+	})
+}
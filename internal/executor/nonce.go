@@ -0,0 +1,23 @@
+package executor
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+)
+
+// nonceCounter distinguishes nonces generated within the clock resolution.
+var nonceCounter uint64
+
+// withNonce appends the comment with a unique value to the command if the
+// session nonce comment marker is set, e.g. "status // 17a3f0c2b.1". Servers
+// ignore the comment while caching proxies see a new command every time.
+func withNonce(ses *config.Session, command string) string {
+	if ses.Nonce == "" {
+		return command
+	}
+
+	return fmt.Sprintf("%s %s %x.%d", command, ses.Nonce, time.Now().UnixNano(), atomic.AddUint64(&nonceCounter, 1))
+}
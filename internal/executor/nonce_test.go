@@ -0,0 +1,55 @@
+package executor_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/gorcon/rcon"
+	"github.com/gorcon/rcon/rcontest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecute_Nonce(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(func(c *rcontest.Context) {
+			// Echo the received command.
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, c.Request().Body()).WriteTo(c.Conn())
+		}),
+	)
+	defer serverRCON.Close()
+
+	// Test commands are sent as is without nonce.
+	t.Run("disabled", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		err := app.Execute(&w, &config.Session{Address: serverRCON.Addr(), Password: "password"}, "status")
+		assert.NoError(t, err)
+		assert.Equal(t, "status\n", w.String())
+	})
+
+	// Test every command gets a unique comment.
+	t.Run("enabled", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", Nonce: "//"}
+
+		err := app.Execute(&w, ses, "status", "status")
+		assert.NoError(t, err)
+
+		responses := strings.Split(strings.TrimSuffix(w.String(), "\n"), "\n"+executor.CommandsResponseSeparator+"\n")
+		assert.Len(t, responses, 2)
+		assert.True(t, strings.HasPrefix(responses[0], "status // "))
+		assert.True(t, strings.HasPrefix(responses[1], "status // "))
+		assert.NotEqual(t, responses[0], responses[1])
+	})
+}
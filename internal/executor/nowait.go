@@ -0,0 +1,43 @@
+package executor
+
+import (
+	"errors"
+	"io"
+	"net"
+	"os"
+	"syscall"
+)
+
+// isClosedAfterSend returns true if the error means that the server closed
+// the connection or did not reply after the command was sent. Protocol
+// libraries have no send-only path, so such errors are treated as success
+// of fire-and-forget commands.
+func isClosedAfterSend(err error) bool {
+	return errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, os.ErrDeadlineExceeded)
+}
+
+// dropClient closes the broken connection so the next command redials.
+func (executor *Executor) dropClient() {
+	if executor.client != nil {
+		_ = executor.client.Close()
+		executor.setClient(nil, poolKey{})
+	}
+}
+
+// setClient replaces the current connection. Only the goroutine running
+// commands sets the client, the lock orders the write with Close. The client
+// set after the halt signal is closed, interrupt may have missed it.
+func (executor *Executor) setClient(client ExecuteCloser, key poolKey) {
+	executor.mu.Lock()
+	defer executor.mu.Unlock()
+
+	executor.client, executor.clientKey = client, key
+
+	if client != nil && executor.halted() {
+		_ = client.Close()
+	}
+}
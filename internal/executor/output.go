@@ -0,0 +1,194 @@
+package executor
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// Supported output formats.
+const (
+	OutputFormatText = "text"
+	OutputFormatJSON = "json"
+)
+
+// Supported response writers.
+const (
+	ResponseToStdout = "stdout"
+	ResponseToStderr = "stderr"
+)
+
+// Stable error codes in JSON output format.
+const (
+	ErrorCodeDial          = "dial_failed"
+	ErrorCodeCommandFailed = "command_failed"
+	ErrorCodeCommandEmpty  = "command_empty"
+	ErrorCodeExecute       = "execute_failed"
+)
+
+var (
+	// ErrUnsupportedOutputFormat is returned when output format is not one
+	// of the supported formats.
+	ErrUnsupportedOutputFormat = errors.New("unsupported output format")
+
+	// ErrUnsupportedResponseTo is returned when response writer is not
+	// stdout or stderr.
+	ErrUnsupportedResponseTo = errors.New("unsupported response writer")
+)
+
+// JSONResult is the command result in JSON output format.
+type JSONResult struct {
+	Command  string     `json:"command"`
+	Response string     `json:"response"`
+	Error    *JSONError `json:"error,omitempty"`
+}
+
+// JSONError is the error in JSON output format.
+type JSONError struct {
+	Message string `json:"message"`
+	Code    string `json:"code"`
+}
+
+// ValidateOutputFormat returns an error if output format is not supported.
+// Empty format is treated as text.
+func ValidateOutputFormat(format string) error {
+	switch format {
+	case "", OutputFormatText, OutputFormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("%w %q: allowed %q and %q", ErrUnsupportedOutputFormat, format, OutputFormatText, OutputFormatJSON)
+	}
+}
+
+// ValidateResponseTo returns an error if response writer is not supported.
+// Empty value is treated as stdout.
+func ValidateResponseTo(value string) error {
+	switch value {
+	case "", ResponseToStdout, ResponseToStderr:
+		return nil
+	default:
+		return fmt.Errorf("%w %q: allowed %q and %q", ErrUnsupportedResponseTo, value, ResponseToStdout, ResponseToStderr)
+	}
+}
+
+// responseWriter returns the writer for command responses.
+func (executor *Executor) responseWriter(ses *config.Session) io.Writer {
+	if ses.ResponseTo == ResponseToStderr {
+		return executor.stderr
+	}
+
+	return executor.w
+}
+
+// teeOutput copies the printed output to the output file. With ses.LogOnly
+// the output is written only to the file. The returned function restores
+// the writers and closes the file.
+func (executor *Executor) teeOutput(ses *config.Session) (func(), error) {
+	file, err := openOutput(Command{Output: ses.OutputFile, Append: ses.OutputAppend})
+	if err != nil {
+		return nil, fmt.Errorf("output file: %w", err)
+	}
+
+	target := &executor.w
+	if ses.ResponseTo == ResponseToStderr {
+		target = &executor.stderr
+	}
+
+	original := *target
+
+	if ses.LogOnly {
+		// Responses are printed to the file instead of the terminal.
+		ses.LogOnly = false
+		*target = file
+	} else {
+		*target = io.MultiWriter(original, file)
+	}
+
+	return func() {
+		*target = original
+		_ = file.Close()
+	}, nil
+}
+
+// newJSONError converts error to JSON error with a stable code.
+func newJSONError(err error, code string) *JSONError {
+	switch {
+	case errors.Is(err, ErrCommandFailed):
+		code = ErrorCodeCommandFailed
+	case errors.Is(err, ErrCommandEmpty):
+		code = ErrorCodeCommandEmpty
+	}
+
+	return &JSONError{Message: err.Error(), Code: code}
+}
+
+// printJSON writes v to w as a single JSON line.
+func printJSON(w io.Writer, v interface{}) {
+	js, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+
+	_, _ = fmt.Fprintln(w, string(js))
+}
+
+// printJSONError writes dial or auth error to w as a top level JSON object.
+func printJSONError(w io.Writer, err error) {
+	printJSON(w, struct {
+		Error *JSONError `json:"error"`
+	}{Error: newJSONError(err, ErrorCodeDial)})
+}
+
+// printJSONResult prints the result as a JSON object and writes it to
+// the log. Log errors are printed to stderr like in text output format,
+// so stdout contains only JSON objects.
+func (executor *Executor) printJSONResult(w io.Writer, ses *config.Session, res Result) error {
+	js := JSONResult{Command: res.Command, Response: res.Response}
+	if res.Err != nil {
+		js.Error = newJSONError(res.Err, ErrorCodeExecute)
+	}
+
+	if !ses.LogOnly {
+		printJSON(w, js)
+	}
+
+	if res.Err != nil && !ses.SkipErrors {
+		return fmt.Errorf("execute: %w", res.Err)
+	}
+
+	if err := executor.writeLog(ses, res.Command, res.Response, res.Raw); err != nil {
+		_, _ = fmt.Fprintln(executor.stderr, fmt.Errorf("log: %w", err))
+	}
+
+	return nil
+}
+
+// printConfig prints the effective session configuration with masked
+// password. The format is JSON in JSON output format and YAML otherwise.
+func (executor *Executor) printConfig(ses *config.Session) error {
+	masked := ses.Masked()
+
+	if ses.OutputFormat == OutputFormatJSON {
+		js, err := json.MarshalIndent(masked, "", "  ")
+		if err != nil {
+			return fmt.Errorf("print config: %w", err)
+		}
+
+		_, _ = fmt.Fprintln(executor.w, string(js))
+
+		return nil
+	}
+
+	ym, err := yaml.Marshal(masked)
+	if err != nil {
+		return fmt.Errorf("print config: %w", err)
+	}
+
+	_, _ = executor.w.Write(ym)
+
+	return nil
+}
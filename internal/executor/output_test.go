@@ -0,0 +1,126 @@
+package executor_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/gorcon/rcon"
+	"github.com/gorcon/rcon/rcontest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecuteJSON(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(func(c *rcontest.Context) {
+			responseBody := "Can I help you?"
+			if c.Request().Body() == "/c error" {
+				responseBody = MockFactorioLuaError
+			}
+
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, responseBody).WriteTo(c.Conn())
+		}),
+	)
+	defer serverRCON.Close()
+
+	t.Run("no error", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", OutputFormat: executor.OutputFormatJSON}
+
+		err := app.Execute(&w, ses, "help", "help")
+		assert.NoError(t, err)
+
+		results := decodeJSONLines(t, w.Bytes())
+		assert.Equal(t, []executor.JSONResult{
+			{Command: "help", Response: "Can I help you?"},
+			{Command: "help", Response: "Can I help you?"},
+		}, results)
+	})
+
+	t.Run("command error", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{
+			Address: serverRCON.Addr(), Password: "password", Game: executor.GameFactorio,
+			OutputFormat: executor.OutputFormatJSON, SkipErrors: true,
+		}
+
+		err := app.Execute(&w, ses, "/c error", "help")
+		assert.NoError(t, err)
+
+		results := decodeJSONLines(t, w.Bytes())
+		assert.Len(t, results, 2)
+		assert.Equal(t, MockFactorioLuaError, results[0].Response)
+		assert.Equal(t, executor.ErrorCodeCommandFailed, results[0].Error.Code)
+		assert.Nil(t, results[1].Error)
+	})
+
+	// Test log error is printed to stderr and the run continues.
+	t.Run("log error", func(t *testing.T) {
+		w := bytes.Buffer{}
+		stderr := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		app.SetStderr(&stderr)
+		defer app.Close()
+
+		ses := &config.Session{
+			Address: serverRCON.Addr(), Password: "password", OutputFormat: executor.OutputFormatJSON,
+			Log: "rcon-test-missing/rcon.log", NoLogMkdir: true,
+		}
+
+		err := app.Execute(&w, ses, "help", "help")
+		assert.NoError(t, err)
+		assert.Len(t, decodeJSONLines(t, w.Bytes()), 2)
+		assert.Equal(t, 2, strings.Count(stderr.String(), "log: log directory does not exist"))
+	})
+
+	t.Run("dial error", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "wrong", OutputFormat: executor.OutputFormatJSON}
+
+		err := app.Execute(&w, ses, "help")
+		assert.Error(t, err)
+
+		results := decodeJSONLines(t, w.Bytes())
+		assert.Len(t, results, 1)
+		assert.Equal(t, executor.ErrorCodeDial, results[0].Error.Code)
+		assert.Equal(t, "auth: rcon: authentication failed", results[0].Error.Message)
+	})
+}
+
+// decodeJSONLines checks that every line of output is a JSON object and
+// returns decoded results.
+func decodeJSONLines(t *testing.T, output []byte) []executor.JSONResult {
+	t.Helper()
+
+	var results []executor.JSONResult
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		var result executor.JSONResult
+		if err := json.Unmarshal(scanner.Bytes(), &result); err != nil {
+			t.Fatalf("not JSON line %q: %v", scanner.Text(), err)
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
@@ -0,0 +1,79 @@
+package executor
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// DefaultPager is the pager used when PAGER environment variable is empty.
+const DefaultPager = "less -R"
+
+// defaultTerminalHeight is used when the terminal size can not be detected.
+const defaultTerminalHeight = 24
+
+// terminalHeight returns the number of lines of the terminal. It is taken
+// from LINES environment variable or stty.
+func terminalHeight(terminal *os.File) int {
+	if lines, err := strconv.Atoi(os.Getenv("LINES")); err == nil && lines > 0 {
+		return lines
+	}
+
+	cmd := exec.Command("stty", "size")
+	cmd.Stdin = terminal
+
+	out, err := cmd.Output()
+	if err != nil {
+		return defaultTerminalHeight
+	}
+
+	// The output is "rows columns".
+	fields := strings.Fields(string(out))
+	if len(fields) == 0 {
+		return defaultTerminalHeight
+	}
+
+	rows, err := strconv.Atoi(fields[0])
+	if err != nil || rows <= 0 {
+		return defaultTerminalHeight
+	}
+
+	return rows
+}
+
+// usePager reports whether responses printed to w are paged. Paging is
+// disabled when w is not a terminal.
+func usePager(w io.Writer, enabled bool) bool {
+	return enabled && isTerminal(w)
+}
+
+// page writes the response to w. If the response is longer than
+// the terminal height it is piped through PAGER instead. The response is
+// written as is if the pager can not be started.
+func page(w io.Writer, response *bytes.Buffer) {
+	terminal, ok := w.(*os.File)
+	if !ok || bytes.Count(response.Bytes(), []byte("\n")) < terminalHeight(terminal) {
+		_, _ = response.WriteTo(w)
+
+		return
+	}
+
+	pager := strings.Fields(os.Getenv("PAGER"))
+	if len(pager) == 0 {
+		pager = strings.Fields(DefaultPager)
+	}
+
+	data := response.Bytes()
+
+	cmd := exec.Command(pager[0], pager[1:]...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stdout = terminal
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		_, _ = w.Write(data)
+	}
+}
@@ -0,0 +1,154 @@
+package executor
+
+import (
+	"time"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+)
+
+// DefaultPoolIdleTimeout is the time after which idle pooled connection is
+// closed. Game servers usually drop idle RCON connections, so such
+// connections are not reused.
+const DefaultPoolIdleTimeout = time.Minute
+
+// poolKey identifies pooled connections by the settings the connection was
+// dialed and authenticated with. The timeout is a part of the key because
+// clients keep the request deadline of the dial.
+type poolKey struct {
+	address     string
+	protocol    string
+	password    string
+	noAuth      bool
+	proxy       string
+	ssh         string
+	sshKey      string
+	tlsCA       string
+	tlsInsecure bool
+	multiPacket bool
+	timeout     time.Duration
+}
+
+// pooledConn is the idle connection waiting in the pool.
+type pooledConn struct {
+	client   ExecuteCloser
+	lastUsed time.Time
+}
+
+// pool keeps idle connections to servers which are not used by the current
+// session, so switching back to them doesn't re-dial.
+type pool struct {
+	idle  time.Duration
+	conns map[poolKey]pooledConn
+}
+
+// newPool creates a pool closing connections after the idle duration.
+func newPool(idle time.Duration) *pool {
+	return &pool{idle: idle, conns: make(map[poolKey]pooledConn)}
+}
+
+// newPoolKey returns the pool key of the session.
+func newPoolKey(ses *config.Session) poolKey {
+	return poolKey{
+		address:     ses.Address,
+		protocol:    ses.Type,
+		password:    ses.Password,
+		noAuth:      ses.NoAuth,
+		proxy:       ses.Proxy,
+		ssh:         ses.SSH,
+		sshKey:      ses.SSHKey,
+		tlsCA:       ses.TLSCA,
+		tlsInsecure: ses.TLSInsecure,
+		multiPacket: ses.MultiPacket,
+		timeout:     ses.Timeout,
+	}
+}
+
+// Put returns the connection to the pool.
+func (p *pool) Put(key poolKey, client ExecuteCloser) {
+	p.evict()
+
+	if old, ok := p.conns[key]; ok {
+		_ = old.client.Close()
+	}
+
+	p.conns[key] = pooledConn{client: client, lastUsed: time.Now()}
+}
+
+// Get takes the connection from the pool. Connections idle longer than
+// the idle duration are considered dead and closed.
+func (p *pool) Get(key poolKey) (ExecuteCloser, bool) {
+	p.evict()
+
+	conn, ok := p.conns[key]
+	if !ok {
+		return nil, false
+	}
+
+	delete(p.conns, key)
+
+	return conn.client, true
+}
+
+// Close closes all pooled connections.
+func (p *pool) Close() {
+	for key, conn := range p.conns {
+		_ = conn.client.Close()
+
+		delete(p.conns, key)
+	}
+}
+
+// evict closes connections idle longer than the idle duration.
+func (p *pool) evict() {
+	for key, conn := range p.conns {
+		if time.Since(conn.lastUsed) > p.idle {
+			_ = conn.client.Close()
+
+			delete(p.conns, key)
+		}
+	}
+}
+
+// checkout makes the pooled connection to the session server current.
+// The current connection to another server is returned to the pool. The
+// pooled connection is not checked, the command dropped by the server is
+// re-sent on the new connection. Returns false if the connection should be
+// dialed.
+func (executor *Executor) checkout(ses *config.Session) bool {
+	key := newPoolKey(ses)
+
+	if executor.client != nil && executor.clientKey == key {
+		return true
+	}
+
+	executor.releaseClient()
+
+	if executor.pool == nil {
+		return false
+	}
+
+	client, ok := executor.pool.Get(key)
+	if !ok {
+		return false
+	}
+
+	executor.setClient(client, key)
+	executor.pooled = true
+
+	return true
+}
+
+// releaseClient returns the current connection to the pool, so it is
+// reused when the session switches back to its server.
+func (executor *Executor) releaseClient() {
+	if executor.client == nil {
+		return
+	}
+
+	if executor.pool == nil {
+		executor.pool = newPool(DefaultPoolIdleTimeout)
+	}
+
+	executor.pool.Put(executor.clientKey, executor.client)
+	executor.setClient(nil, poolKey{})
+}
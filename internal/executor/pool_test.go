@@ -0,0 +1,154 @@
+package executor_test
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/gorcon/rcon"
+	"github.com/gorcon/rcon/rcontest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecutor_Pool(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []string
+	)
+
+	newServer := func(name string) *rcontest.Server {
+		return rcontest.NewServer(
+			rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+			rcontest.SetCommandHandler(func(c *rcontest.Context) {
+				mu.Lock()
+				received = append(received, c.Request().Body())
+				mu.Unlock()
+
+				rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, name).WriteTo(c.Conn())
+			}),
+		)
+	}
+
+	serverA := newServer("a")
+	defer serverA.Close()
+
+	serverB := newServer("b")
+	defer serverB.Close()
+
+	// Connections are reused when switching between servers.
+	t.Run("reuse by address", func(t *testing.T) {
+		w := bytes.Buffer{}
+		events := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		app.SetEvents(&events)
+		defer app.Close()
+
+		sesA := &config.Session{Address: serverA.Addr(), Password: "password"}
+		sesB := &config.Session{Address: serverB.Addr(), Password: "password"}
+
+		for _, ses := range []*config.Session{sesA, sesB, sesA, sesB} {
+			err := app.Execute(&w, ses, "whoami")
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, "a\nb\na\nb\n", w.String())
+		assert.Equal(t, 2, strings.Count(events.String(), executor.EventDialStart))
+
+		// Reused connections are not checked with extra commands.
+		mu.Lock()
+		assert.Equal(t, []string{"whoami", "whoami", "whoami", "whoami"}, received)
+		mu.Unlock()
+	})
+
+	// Connections authenticated with another password are not reused.
+	t.Run("password in key", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		sesA := &config.Session{Address: serverA.Addr(), Password: "password"}
+		sesB := &config.Session{Address: serverB.Addr(), Password: "password"}
+		wrong := &config.Session{Address: serverA.Addr(), Password: "wrong"}
+
+		assert.NoError(t, app.Execute(&w, sesA, "whoami"))
+		assert.NoError(t, app.Execute(&w, sesB, "whoami"))
+
+		err := app.Execute(&w, wrong, "whoami")
+		assert.ErrorIs(t, err, rcon.ErrAuthFailed)
+	})
+
+	// Commands dropped by the server on pooled connections are re-sent.
+	t.Run("dropped connection", func(t *testing.T) {
+		addrC := newDroppingServer(t, "c")
+
+		w := bytes.Buffer{}
+		events := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		app.SetEvents(&events)
+		defer app.Close()
+
+		sesC := &config.Session{Address: addrC, Password: "password"}
+		sesB := &config.Session{Address: serverB.Addr(), Password: "password"}
+
+		for _, ses := range []*config.Session{sesC, sesB, sesC} {
+			err := app.Execute(&w, ses, "whoami")
+			assert.NoError(t, err)
+		}
+
+		// The command is re-sent on the new connection to the server.
+		assert.Equal(t, "c\nb\nc\n", w.String())
+		assert.Equal(t, 3, strings.Count(events.String(), executor.EventDialStart))
+	})
+}
+
+// newDroppingServer starts RCON server which accepts any password and
+// closes the connection after the first command response.
+func newDroppingServer(t *testing.T, name string) string {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				for {
+					request := &rcon.Packet{}
+					if _, err := request.ReadFrom(conn); err != nil {
+						return
+					}
+
+					if request.Type == rcon.SERVERDATA_AUTH {
+						_, _ = rcon.NewPacket(rcon.SERVERDATA_AUTH_RESPONSE, request.ID, "").WriteTo(conn)
+
+						continue
+					}
+
+					_, _ = rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, request.ID, name).WriteTo(conn)
+
+					return
+				}
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
@@ -0,0 +1,90 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/games"
+)
+
+// Games with error patterns of responses.
+const (
+	GameFactorio  = games.Factorio
+	GameMinecraft = games.Minecraft
+)
+
+var (
+	// ErrCommandFailed is returned when the server executed the command
+	// but reported an error in the response.
+	ErrCommandFailed = errors.New("command failed")
+
+	// ErrUnsupportedGame is returned when there is no preset for the game.
+	ErrUnsupportedGame = errors.New("unsupported game")
+)
+
+// ValidateGame returns an error listing the available presets if there is
+// no preset for the game. Empty game is valid.
+func ValidateGame(game string) error {
+	if _, ok := games.Get(game); ok || game == "" {
+		return nil
+	}
+
+	return fmt.Errorf("%w %q: supported games are %s", ErrUnsupportedGame, game, strings.Join(games.Names(), ", "))
+}
+
+// applyGamePreset fills the session type and address port from the game
+// preset. The type is kept if typeSet is true, the port is added only to
+// addresses without it.
+func applyGamePreset(ses *config.Session, typeSet bool) {
+	preset, ok := games.Get(ses.Game)
+	if !ok {
+		return
+	}
+
+	if !typeSet {
+		ses.Type = preset.Type
+	}
+
+	ses.Address = withDefaultPort(ses.Address, preset.DefaultPort)
+	for i, address := range ses.Addresses {
+		ses.Addresses[i] = withDefaultPort(address, preset.DefaultPort)
+	}
+}
+
+// withDefaultPort adds the port to the address if it has no port.
+func withDefaultPort(address string, port string) string {
+	if address == "" || port == "" {
+		return address
+	}
+
+	if _, _, err := net.SplitHostPort(address); err == nil {
+		return address
+	}
+
+	return net.JoinHostPort(address, port)
+}
+
+// processResponse returns ErrCommandFailed with the matched message if
+// the response matches error patterns of the game preset.
+func processResponse(game string, response string) error {
+	preset, _ := games.Get(game)
+	if message := preset.MatchError(response); message != "" {
+		return fmt.Errorf("%w: %s", ErrCommandFailed, message)
+	}
+
+	return nil
+}
+
+// stripLeadingSlash removes the leading slash copied from in-game chat if
+// it is enabled by flag or by the game preset, e.g. Minecraft RCON doesn't
+// accept commands with it.
+func stripLeadingSlash(ses *config.Session, command string) string {
+	if preset, _ := games.Get(ses.Game); ses.StripLeadingSlash || preset.StripLeadingSlash {
+		return strings.TrimPrefix(command, "/")
+	}
+
+	return command
+}
@@ -0,0 +1,150 @@
+package executor_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/gorcon/rcon"
+	"github.com/gorcon/rcon/rcontest"
+	"github.com/stretchr/testify/assert"
+)
+
+const MockFactorioLuaError = `Cannot execute command. Error: [string "game.print(foo.bar)"]:1: attempt to index global 'foo' (a nil value)`
+
+func TestValidateGame(t *testing.T) {
+	assert.NoError(t, executor.ValidateGame(""))
+	assert.NoError(t, executor.ValidateGame(executor.GameFactorio))
+	assert.NoError(t, executor.ValidateGame(executor.GameMinecraft))
+	assert.EqualError(t, executor.ValidateGame("pong"), `unsupported game "pong": supported games are 7dtd, ark, factorio, minecraft, palworld, rust, zomboid`)
+}
+
+func TestFactorioProcessor(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(func(c *rcontest.Context) {
+			responseBody := "ok"
+			if c.Request().Body() == "/c game.print(foo.bar)" {
+				responseBody = MockFactorioLuaError
+			}
+
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, responseBody).WriteTo(c.Conn())
+		}),
+	)
+	defer serverRCON.Close()
+
+	ses := &config.Session{Address: serverRCON.Addr(), Password: "password", Game: executor.GameFactorio}
+
+	t.Run("no error", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		err := app.Execute(&w, ses, "/players")
+		assert.NoError(t, err)
+		assert.Equal(t, "ok\n", w.String())
+	})
+
+	t.Run("lua error", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		err := app.Execute(&w, ses, "/c game.print(foo.bar)")
+		assert.ErrorIs(t, err, executor.ErrCommandFailed)
+		assert.Equal(t, MockFactorioLuaError+"\n", w.String())
+	})
+}
+
+func TestMinecraftProcessor(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(func(c *rcontest.Context) {
+			responseBody := "There are 0 of a max of 20 players online"
+			if strings.HasPrefix(c.Request().Body(), "/") {
+				responseBody = "Unknown or incomplete command"
+			}
+
+			if c.Request().Body() == "colored" {
+				responseBody = "§cUnknown or incomplete command"
+			}
+
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, responseBody).WriteTo(c.Conn())
+		}),
+	)
+	defer serverRCON.Close()
+
+	tests := []struct {
+		name string
+		ses  config.Session
+	}{
+		{"minecraft game", config.Session{Game: executor.GameMinecraft}},
+		{"strip leading slash", config.Session{StripLeadingSlash: true}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			logFileName := "rcon-test-minecraft.log"
+			defer os.Remove(logFileName)
+
+			w := bytes.Buffer{}
+
+			app := executor.NewExecutor(nil, &w, "")
+			defer app.Close()
+
+			tt.ses.Address = serverRCON.Addr()
+			tt.ses.Password = "password"
+			tt.ses.Log = logFileName
+
+			err := app.Execute(&w, &tt.ses, "/list")
+			assert.NoError(t, err)
+			assert.Equal(t, "There are 0 of a max of 20 players online\n", w.String())
+
+			// The command is logged as it was sent.
+			data, err := os.ReadFile(logFileName)
+			assert.NoError(t, err)
+			assert.Contains(t, string(data), ": list\n")
+		})
+	}
+
+	// Test unknown command is reported as failed with minecraft game only.
+	t.Run("unknown command", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password"}
+
+		err := app.Execute(&w, ses, "/list")
+		assert.NoError(t, err)
+
+		ses.Game = executor.GameMinecraft
+
+		err = app.Execute(&w, ses, "//list")
+		assert.ErrorIs(t, err, executor.ErrCommandFailed)
+	})
+
+	// Test colored error response matches the patterns.
+	t.Run("colored unknown command", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{
+			Address: serverRCON.Addr(), Password: "password", Game: executor.GameMinecraft, ForceColor: true,
+		}
+
+		err := app.Execute(&w, ses, "colored")
+		assert.ErrorIs(t, err, executor.ErrCommandFailed)
+		assert.Contains(t, w.String(), "\x1b[")
+	})
+}
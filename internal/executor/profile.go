@@ -0,0 +1,61 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+// Suffixes of profile files appended to the profile prefix.
+const (
+	ProfileCPUSuffix    = ".cpu.pprof"
+	ProfileMemorySuffix = ".mem.pprof"
+)
+
+// startProfile starts CPU profiling to the file with the prefix. The
+// returned function stops CPU profiling and writes the heap profile.
+func startProfile(prefix string) (func() error, error) {
+	cpu, err := os.Create(prefix + ProfileCPUSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("profile: %w", err)
+	}
+
+	if err = pprof.StartCPUProfile(cpu); err != nil {
+		_ = cpu.Close()
+
+		return nil, fmt.Errorf("profile: %w", err)
+	}
+
+	stop := func() error {
+		pprof.StopCPUProfile()
+
+		if err := cpu.Close(); err != nil {
+			return fmt.Errorf("profile: %w", err)
+		}
+
+		return writeHeapProfile(prefix + ProfileMemorySuffix)
+	}
+
+	return stop, nil
+}
+
+// writeHeapProfile writes the heap profile to the file.
+func writeHeapProfile(name string) error {
+	file, err := os.Create(name)
+	if err != nil {
+		return fmt.Errorf("profile: %w", err)
+	}
+
+	// Collect garbage to get up-to-date allocation statistics.
+	runtime.GC()
+
+	err = pprof.WriteHeapProfile(file)
+
+	if err = errors.Join(err, file.Close()); err != nil {
+		return fmt.Errorf("profile: %w", err)
+	}
+
+	return nil
+}
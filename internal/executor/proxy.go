@@ -0,0 +1,224 @@
+package executor
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"golang.org/x/net/proxy"
+)
+
+// Proxy URL schemes.
+const (
+	ProxySOCKS5  = "socks5"
+	ProxySOCKS5H = "socks5h"
+	ProxyHTTP    = "http"
+)
+
+var (
+	// ErrUnsupportedProxy is returned when proxy URL can't be parsed or its
+	// scheme is not supported.
+	ErrUnsupportedProxy = errors.New("unsupported proxy")
+
+	// ErrProxyConnect is returned when HTTP proxy refuses the CONNECT
+	// request.
+	ErrProxyConnect = errors.New("proxy connect failed")
+)
+
+// dialFunc connects to the address on the named network.
+type dialFunc func(network, address string) (net.Conn, error)
+
+// ValidateProxy returns an error if the session proxy URL is not
+// supported.
+func ValidateProxy(ses *config.Session) error {
+	if ses.Proxy == "" {
+		return nil
+	}
+
+	_, err := newProxyDialer(ses.Proxy, ses.Timeout)
+
+	return err
+}
+
+// newProxyDialer returns the function dialing addresses through the proxy.
+// SOCKS5 proxies are dialed by x/net/proxy, HTTP proxies with the CONNECT
+// request.
+func newProxyDialer(rawURL string, timeout time.Duration) (dialFunc, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %w", ErrUnsupportedProxy, rawURL, err)
+	}
+
+	forward := &net.Dialer{Timeout: timeout}
+
+	switch u.Scheme {
+	case ProxySOCKS5, ProxySOCKS5H:
+		dialer, err := proxy.FromURL(u, forward)
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %w", ErrUnsupportedProxy, rawURL, err)
+		}
+
+		return dialer.Dial, nil
+	case ProxyHTTP:
+		return func(network, address string) (net.Conn, error) {
+			return dialHTTPProxy(forward, u, address)
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w %q: allowed %q, %q and %q schemes",
+			ErrUnsupportedProxy, rawURL, ProxySOCKS5, ProxySOCKS5H, ProxyHTTP)
+	}
+}
+
+// dialHTTPProxy opens the tunnel to the address with HTTP CONNECT request.
+func dialHTTPProxy(forward *net.Dialer, u *url.URL, address string) (net.Conn, error) {
+	conn, err := forward.Dial("tcp", u.Host)
+	if err != nil {
+		return nil, fmt.Errorf("proxy: %w", err)
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: make(http.Header),
+	}
+
+	if u.User != nil {
+		password, _ := u.User.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(u.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	if forward.Timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(forward.Timeout))
+	}
+
+	if err = req.Write(conn); err != nil {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("proxy: %w", err)
+	}
+
+	// The server sends nothing before the client request, so the reader
+	// doesn't buffer bytes of the tunneled connection.
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("proxy: %w", err)
+	}
+
+	_ = resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("%w: %s", ErrProxyConnect, resp.Status)
+	}
+
+	_ = conn.SetDeadline(time.Time{})
+
+	return conn, nil
+}
+
+// dialAddress returns the address the client library must connect to.
+// With proxy, SSH or telnets the remote address is dialed through them and
+// the local address forwarding to the connection is returned, because the
+// libraries open TCP connections themselves.
+func (executor *Executor) dialAddress(ses *config.Session) (string, error) {
+	if !tunneled(ses) && ses.Type != config.ProtocolTELNETS {
+		return ses.Address, nil
+	}
+
+	dial := (&net.Dialer{Timeout: ses.Timeout}).Dial
+
+	var err error
+
+	if ses.Proxy != "" {
+		if dial, err = newProxyDialer(ses.Proxy, ses.Timeout); err != nil {
+			return "", err
+		}
+	}
+
+	// SSH connection is opened through the proxy if both are set.
+	if ses.SSH != "" {
+		if dial, err = executor.sshDialer(ses, dial); err != nil {
+			return "", err
+		}
+	}
+
+	if ses.Type == config.ProtocolTELNETS {
+		if dial, err = tlsDialer(ses, dial); err != nil {
+			return "", err
+		}
+	}
+
+	return dialTunnel(dial, ses.Address, ses.Timeout)
+}
+
+// tunneled reports whether the connection is routed through proxy or SSH.
+func tunneled(ses *config.Session) bool {
+	return ses.Proxy != "" || ses.SSH != ""
+}
+
+// dialTunnel dials the address and returns the local address accepting
+// a single connection which is forwarded to it. The listener is closed
+// after the timeout if the client doesn't connect.
+func dialTunnel(dial dialFunc, address string, timeout time.Duration) (string, error) {
+	remote, err := dial("tcp", address)
+	if err != nil {
+		return "", err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		_ = remote.Close()
+
+		return "", fmt.Errorf("tunnel: %w", err)
+	}
+
+	if timeout > 0 {
+		_ = listener.(*net.TCPListener).SetDeadline(time.Now().Add(timeout))
+	}
+
+	go func() {
+		local, err := listener.Accept()
+		_ = listener.Close()
+
+		if err != nil {
+			_ = remote.Close()
+
+			return
+		}
+
+		forward(local, remote)
+	}()
+
+	return listener.Addr().String(), nil
+}
+
+// forward copies data between connections in both directions until one of
+// them is closed.
+func forward(local net.Conn, remote net.Conn) {
+	done := make(chan struct{}, 2)
+
+	cp := func(dst io.Writer, src io.Reader) {
+		_, _ = io.Copy(dst, src)
+		done <- struct{}{}
+	}
+
+	go cp(remote, local)
+	go cp(local, remote)
+
+	<-done
+
+	_ = local.Close()
+	_ = remote.Close()
+}
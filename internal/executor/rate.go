@@ -0,0 +1,44 @@
+package executor
+
+import (
+	"time"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+)
+
+// rateLimiter is a token bucket limiter with the bucket size of one token.
+// It spaces commands evenly so bursts never exceed the server threshold.
+type rateLimiter struct {
+	interval time.Duration
+	next     time.Time
+}
+
+// newRateLimiter creates a limiter allowing rate commands per second.
+func newRateLimiter(rate float64) *rateLimiter {
+	return &rateLimiter{interval: time.Duration(float64(time.Second) / rate)}
+}
+
+// Wait blocks until the next token is available.
+func (l *rateLimiter) Wait() {
+	now := time.Now()
+	if l.next.After(now) {
+		time.Sleep(l.next.Sub(now))
+		now = l.next
+	}
+
+	l.next = now.Add(l.interval)
+}
+
+// throttle blocks until the command can be sent according to the session
+// rate limit. The limiter is shared between all commands of the executor.
+func (executor *Executor) throttle(ses *config.Session) {
+	if ses.Rate <= 0 {
+		return
+	}
+
+	if executor.limiter == nil {
+		executor.limiter = newRateLimiter(ses.Rate)
+	}
+
+	executor.limiter.Wait()
+}
@@ -0,0 +1,96 @@
+package executor
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"syscall"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+)
+
+// resend re-dials and sends the command once more. Some WebRCON servers
+// answer with an empty response instead of reporting the dropped connection.
+func (executor *Executor) resend(ses *config.Session, command string) (string, error) {
+	executor.dropClient()
+
+	if err := executor.Dial(ses); err != nil {
+		return "", err
+	}
+
+	executor.emit(Event{Event: EventCommandSent, Command: command})
+
+	return executor.client.Execute(terminate(ses, withNonce(ses, command)))
+}
+
+// dropUncorrelated closes the connection after the command timed out if
+// the client can't match responses to requests. The RCON library uses the
+// same packet ID for every command, so the late response would be read as
+// the response of the next command. Multi packet connection skips packets
+// with stale IDs and is kept.
+func (executor *Executor) dropUncorrelated(err error) {
+	if _, ok := executor.client.(*multiPacketConn); ok {
+		return
+	}
+
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		executor.dropClient()
+	}
+}
+
+// retryOnTimeout re-sends the timed out command up to the session retry
+// limit while the retry budget allows. The command is sent on the same
+// connection if the client matches responses to requests, otherwise the
+// connection is dropped by dropUncorrelated and dialed again. Other errors
+// are not retried, retries stop on the halt signal.
+func (executor *Executor) retryOnTimeout(ses *config.Session, command string, result string, err error,
+) (string, error) {
+	for i := 0; i < ses.TimeoutRetries && isTimeout(err) && !executor.halted() && executor.spendRetry(ses); i++ {
+		executor.dropUncorrelated(err)
+
+		if err = executor.Dial(ses); err != nil {
+			return "", err
+		}
+
+		executor.emit(Event{Event: EventCommandSent, Command: command})
+
+		result, err = executor.client.Execute(terminate(ses, withNonce(ses, command)))
+	}
+
+	return result, err
+}
+
+// isDropped reports whether the server closed the connection before the
+// command was answered.
+func isDropped(err error) bool {
+	return errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.EPIPE)
+}
+
+// spendRetry reports whether the retry budget of the session allows one more
+// retry and counts it. The budget is shared by all commands of the executor.
+func (executor *Executor) spendRetry(ses *config.Session) bool {
+	if ses.RetryBudget > 0 && executor.retries >= ses.RetryBudget {
+		return false
+	}
+
+	executor.retries++
+
+	return true
+}
+
+// isTimeout reports whether the error is caused by the expired deadline
+// rather than by the connection failure.
+func isTimeout(err error) bool {
+	if errors.Is(err, os.ErrDeadlineExceeded) || errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
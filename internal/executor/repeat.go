@@ -0,0 +1,87 @@
+package executor
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+)
+
+// repeater holds the state of the repeat loop between iterations.
+type repeater struct {
+	executor *Executor
+	w        io.Writer
+	ses      *config.Session
+
+	// last contains the last printed response per command.
+	last    []string
+	printed bool
+}
+
+// Repeat executes commands every ses.Repeat interval. The loop stops after
+// ses.RepeatCount iterations or runs until interrupted if count is not set.
+// With ses.OnChange a command response is printed only when it differs from
+// the response of the previous iteration.
+// With ses.Diff only added and removed lines of the changed response are
+// printed.
+func (executor *Executor) Repeat(w io.Writer, ses *config.Session, commands ...Command) error {
+	if len(commands) == 0 {
+		return ErrCommandEmpty
+	}
+
+	rep := repeater{executor: executor, w: w, ses: ses, last: make([]string, len(commands))}
+
+	for iteration := 1; ; iteration++ {
+		if err := rep.iterate(commands, iteration == 1); err != nil {
+			return err
+		}
+
+		if ses.RepeatCount > 0 && iteration >= ses.RepeatCount {
+			return nil
+		}
+
+		select {
+		case <-executor.halt:
+			return ErrHaltTimeout
+		case <-time.After(ses.Repeat):
+		}
+	}
+}
+
+// iterate executes commands once and prints responses which need to be
+// printed.
+func (rep *repeater) iterate(commands []Command, first bool) error {
+	for i, command := range commands {
+		var buffer bytes.Buffer
+		if err := rep.executor.ExecuteCommands(&buffer, rep.ses, command); err != nil {
+			return err
+		}
+
+		if rep.ses.LastOnly && i+1 != len(commands) {
+			continue
+		}
+
+		response := buffer.String()
+		if (rep.ses.OnChange || rep.ses.Diff) && !first && response == rep.last[i] {
+			continue
+		}
+
+		last := rep.last[i]
+		rep.last[i] = response
+
+		if rep.printed && !rep.ses.Labels {
+			printSeparator(rep.w, rep.ses)
+		}
+
+		if rep.ses.Diff && !first {
+			printDiff(rep.w, rep.ses, last, response)
+		} else {
+			_, _ = io.WriteString(rep.w, response)
+		}
+
+		rep.printed = true
+	}
+
+	return nil
+}
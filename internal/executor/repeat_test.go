@@ -0,0 +1,120 @@
+package executor_test
+
+import (
+	"bytes"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/crasssr/rcon-cli/internal/colors"
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/gorcon/rcon"
+	"github.com/gorcon/rcon/rcontest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepeat(t *testing.T) {
+	var counter int32
+
+	// Responds with the number which is increased on every second request.
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(func(c *rcontest.Context) {
+			n := atomic.AddInt32(&counter, 1)
+			responseBody := strconv.Itoa(int((n + 1) / 2))
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, responseBody).WriteTo(c.Conn())
+		}),
+	)
+	defer serverRCON.Close()
+
+	t.Run("every iteration", func(t *testing.T) {
+		atomic.StoreInt32(&counter, 0)
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", Repeat: time.Millisecond, RepeatCount: 3}
+
+		err := app.Repeat(&w, ses, executor.NewCommands("count")...)
+		assert.NoError(t, err)
+
+		sep := executor.CommandsResponseSeparator
+		assert.Equal(t, "1\n"+sep+"\n1\n"+sep+"\n2\n", w.String())
+	})
+
+	t.Run("on change", func(t *testing.T) {
+		atomic.StoreInt32(&counter, 0)
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{
+			Address: serverRCON.Addr(), Password: "password", Repeat: time.Millisecond, RepeatCount: 3, OnChange: true,
+		}
+
+		err := app.Repeat(&w, ses, executor.NewCommands("count")...)
+		assert.NoError(t, err)
+		assert.Equal(t, "1\n"+executor.CommandsResponseSeparator+"\n2\n", w.String())
+	})
+	t.Run("diff", func(t *testing.T) {
+		atomic.StoreInt32(&counter, 0)
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{
+			Address: serverRCON.Addr(), Password: "password", Repeat: time.Millisecond, RepeatCount: 3, Diff: true,
+		}
+
+		err := app.Repeat(&w, ses, executor.NewCommands("count")...)
+		assert.NoError(t, err)
+		assert.Equal(t, "1\n"+executor.CommandsResponseSeparator+"\n\033[31m- 1\033[0m\n\033[32m+ 2\033[0m\n", w.String())
+	})
+
+	t.Run("diff color mode", func(t *testing.T) {
+		atomic.StoreInt32(&counter, 0)
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{
+			Address: serverRCON.Addr(), Password: "password", Repeat: time.Millisecond, RepeatCount: 3, Diff: true,
+			ColorMode: colors.Mode256,
+		}
+
+		err := app.Repeat(&w, ses, executor.NewCommands("count")...)
+		assert.NoError(t, err)
+
+		removed := colors.Minecraft['4'].ToANSI(colors.Mode256)
+		added := colors.Minecraft['2'].ToANSI(colors.Mode256)
+		assert.Equal(t, "1\n"+executor.CommandsResponseSeparator+"\n"+removed+"- 1\033[0m\n"+added+"+ 2\033[0m\n", w.String())
+		assert.NotContains(t, w.String(), "\033[31m")
+	})
+
+	t.Run("diff no color", func(t *testing.T) {
+		atomic.StoreInt32(&counter, 0)
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{
+			Address: serverRCON.Addr(), Password: "password", Repeat: time.Millisecond, RepeatCount: 3, Diff: true, NoColor: true,
+		}
+
+		err := app.Repeat(&w, ses, executor.NewCommands("count")...)
+		assert.NoError(t, err)
+		assert.Equal(t, "1\n"+executor.CommandsResponseSeparator+"\n- 1\n+ 2\n", w.String())
+	})
+}
@@ -0,0 +1,369 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/logger"
+)
+
+// Result contains the processed response of the command executed on
+// the remote server.
+type Result struct {
+	Command  string
+	Response string
+	Err      error
+	Duration time.Duration
+	// Raw is the response as it was received from the server, before
+	// trimming and rendering of color codes.
+	Raw string
+
+	// index is the index of the executed command, steps of the chained
+	// command share it.
+	index int
+}
+
+// TODO: Group environments with identical responses with --dedup flag when
+// broadcasting commands to several environments lands. Results are already
+// collected here to compare them.
+
+// Results sends commands to Execute to the remote server and returns their
+// results without printing. Execution stops on the first failed command
+// unless errors are skipped.
+func (executor *Executor) Results(ses *config.Session, commands ...string) ([]Result, error) {
+	if err := executor.Dial(ses); err != nil {
+		return nil, fmt.Errorf("execute: %w", err)
+	}
+
+	results, err := executor.results(ses, NewCommands(commands...))
+	if err != nil {
+		return results, err
+	}
+
+	if n := len(results); n > 0 && results[n-1].Err != nil && !ses.SkipErrors {
+		return results, fmt.Errorf("execute: %w", results[n-1].Err)
+	}
+
+	return results, nil
+}
+
+// results executes commands and returns the results of every executed step
+// of chained commands. A step after ChainAnd is skipped if the previous step
+// failed. Execution stops after the failed command unless errors are
+// skipped. ErrHaltTimeout is returned with the results collected before
+// the halt.
+func (executor *Executor) results(ses *config.Session, commands []Command) ([]Result, error) {
+	results := make([]Result, 0, len(commands))
+
+	for i, command := range commands {
+		if executor.halted() {
+			return results, ErrHaltTimeout
+		}
+
+		executor.printProgress(ses, command.Text)
+
+		failed := false
+
+		for _, step := range chainSteps(ses, command) {
+			if step.operator == ChainAnd && failed {
+				continue
+			}
+
+			sub := command
+			sub.Text = step.text
+
+			res := executor.commandResult(ses, sub)
+			res.index = i
+			results = append(results, res)
+
+			failed = res.Err != nil && !ses.SkipErrors
+		}
+
+		if failed {
+			break
+		}
+	}
+
+	return results, nil
+}
+
+// commandResult sends the command with its own timeout if it is set.
+func (executor *Executor) commandResult(ses *config.Session, command Command) Result {
+	if command.Text == "" {
+		return Result{Err: ErrCommandEmpty}
+	}
+
+	// Client libraries set the timeout on dial so the command with its own
+	// timeout is sent over a separate connection.
+	if command.Timeout > 0 && command.Timeout != ses.Timeout {
+		executor.dropClient()
+		defer executor.dropClient()
+
+		custom := *ses
+		custom.Timeout = command.Timeout
+		ses = &custom
+	}
+
+	return executor.result(ses, command.Text)
+}
+
+// result sends command to the remote server and measures the duration
+// of the request.
+func (executor *Executor) result(ses *config.Session, command string) Result {
+	command = stripLeadingSlash(ses, command)
+
+	start := time.Now()
+	response, raw, err := executor.requestRaw(ses, command)
+
+	res := Result{Command: command, Response: response, Err: err, Duration: time.Since(start), Raw: raw}
+	executor.warnSlow(ses, res)
+
+	return res
+}
+
+// warnSlow prints a warning to stderr if the command took longer than
+// the session slow threshold.
+func (executor *Executor) warnSlow(ses *config.Session, res Result) {
+	if ses.WarnSlow > 0 && res.Duration > ses.WarnSlow {
+		_, _ = fmt.Fprintf(executor.stderr, "warning: slow command %q took %s (threshold %s)\n",
+			res.Command, res.Duration.Round(time.Millisecond), ses.WarnSlow)
+	}
+}
+
+// executeMerged sends commands to the remote server and prints their
+// responses joined with a newline as the result of one command. Output
+// redirection of commands is not applied.
+func (executor *Executor) executeMerged(w io.Writer, ses *config.Session, commands []Command) error {
+	results, haltErr := executor.results(ses, commands)
+
+	var (
+		merged    Result
+		texts     = make([]string, 0, len(results))
+		responses = make([]string, 0, len(results))
+		raws      = make([]string, 0, len(results))
+	)
+
+	for _, res := range results {
+		texts = append(texts, res.Command)
+		merged.Duration += res.Duration
+
+		if res.Response != "" {
+			responses = append(responses, res.Response)
+		}
+
+		if res.Raw != "" {
+			raws = append(raws, res.Raw)
+		}
+
+		if res.Err != nil {
+			merged.Err = res.Err
+		}
+	}
+
+	merged.Command = strings.Join(texts, "; ")
+	merged.Response = strings.Join(responses, "\n")
+	merged.Raw = strings.Join(raws, "\n")
+
+	if err := executor.printResult(w, ses, merged); err != nil {
+		return err
+	}
+
+	return haltErr
+}
+
+// printResults prints results of the commands in the session output format.
+// Labels and separators are printed per command, steps of the chained
+// command are printed together. Only the last command is printed with
+// LastOnly. The error of the failed command is returned.
+func (executor *Executor) printResults(w io.Writer, ses *config.Session, commands []Command, results []Result) error {
+	for start := 0; start < len(results); {
+		index := results[start].index
+
+		end := start + 1
+		for end < len(results) && results[end].index == index {
+			end++
+		}
+
+		last := index+1 == len(commands)
+
+		// Only the response of the last command is printed, previous
+		// commands prime the server state.
+		cw := w
+		if ses.LastOnly && !last {
+			cw = io.Discard
+		}
+
+		printLabel(cw, ses, commands[index].Text)
+
+		if err := executor.printCommand(cw, ses, commands[index], results[start:end]); err != nil {
+			return err
+		}
+
+		if !last && !ses.LastOnly && !ses.Labels {
+			printSeparator(w, ses)
+		}
+
+		start = end
+	}
+
+	return nil
+}
+
+// printCommand prints results of the command steps. The responses are
+// written to the file if the command has output redirection. Errors of
+// the steps followed by another step are printed, the error of the last
+// step is returned.
+func (executor *Executor) printCommand(w io.Writer, ses *config.Session, command Command, steps []Result) error {
+	out := w
+
+	if command.Output != "" {
+		file, err := openOutput(command)
+		if err != nil {
+			return fmt.Errorf("execute: %w", err)
+		}
+		defer file.Close()
+
+		out = file
+	}
+
+	var last error
+
+	for _, res := range steps {
+		// The error is not returned if the chain goes on, so it is printed.
+		if last != nil {
+			_, _ = fmt.Fprintln(w, last)
+		}
+
+		last = executor.printResult(out, ses, res)
+	}
+
+	return last
+}
+
+// printResult prints the result in the session output format.
+func (executor *Executor) printResult(w io.Writer, ses *config.Session, res Result) error {
+	if ses.OutputFormat == OutputFormatJSON {
+		return executor.printJSONResult(w, ses, res)
+	}
+
+	return executor.printText(w, ses, res)
+}
+
+// printText prints the result in text output format and writes it to the
+// log. Log errors are printed and don't stop the execution.
+func (executor *Executor) printText(w io.Writer, ses *config.Session, res Result) error {
+	response := flatten(ses, res.Response)
+
+	if response != "" && !ses.LogOnly {
+		_, _ = fmt.Fprintln(w, response)
+	}
+
+	if res.Err != nil {
+		if !ses.SkipErrors {
+			return fmt.Errorf("execute: %w", res.Err)
+		}
+
+		_, _ = fmt.Fprintln(w, fmt.Errorf("execute: %w", res.Err))
+	}
+
+	if err := executor.writeLog(ses, res.Command, response, res.Raw); err != nil {
+		_, _ = fmt.Fprintln(w, fmt.Errorf("log: %w", err))
+	}
+
+	return nil
+}
+
+// writeLog saves the command and response to the session log file in
+// the log template or log format. Missing directories of the log file are
+// created unless disabled. With flush interval or sync the file is kept
+// open by the log writer.
+func (executor *Executor) writeLog(ses *config.Session, command string, response string, raw string) error {
+	if ses.NoLogMkdir {
+		if err := logger.CheckDir(ses.Log); err != nil {
+			return err
+		}
+	}
+
+	// Disable logging if log file name is empty.
+	if ses.Log == "" {
+		return nil
+	}
+
+	line, err := executor.logLine(ses, command, response, raw)
+	if err != nil {
+		return err
+	}
+
+	if ses.LogFlushInterval <= 0 && !ses.LogSync {
+		return logger.AppendLine(ses.Log, line)
+	}
+
+	w, err := executor.openLogWriter(ses)
+	if err != nil {
+		return err
+	}
+
+	return w.WriteLine(line)
+}
+
+// logLine returns the log record in the log template or log format. JSON
+// and HTML formats record the raw response, so bytes of binary responses
+// are preserved by base64 encoding and color codes are converted to HTML
+// regardless of the terminal color mode.
+func (executor *Executor) logLine(ses *config.Session, command string, response string, raw string) (string, error) {
+	if ses.LogTemplate == "" {
+		if ses.LogFormat == logger.FormatJSON || ses.LogFormat == logger.FormatHTML {
+			response = raw
+		}
+
+		return logger.FormatLine(ses.LogFormat, ses.Address, command, response)
+	}
+
+	if executor.logTemplate == nil {
+		tmpl, err := logger.ParseTemplate(ses.LogTemplate)
+		if err != nil {
+			return "", err
+		}
+
+		executor.logTemplate = tmpl
+	}
+
+	protocol := ses.Type
+	if protocol == "" {
+		protocol = config.DefaultProtocol
+	}
+
+	entry := logger.Entry{Address: ses.Address, Type: protocol, Command: command, Response: response}
+
+	return logger.TemplateLine(executor.logTemplate, entry)
+}
+
+// openLogWriter returns the log writer of the session log file. The writer
+// of another file, e.g. after switching environments, is closed.
+func (executor *Executor) openLogWriter(ses *config.Session) (*logger.Writer, error) {
+	if executor.logWriter != nil && executor.logWriter.Name() == ses.Log {
+		return executor.logWriter, nil
+	}
+
+	executor.closeLogWriter()
+
+	w, err := logger.NewWriter(ses.Log, ses.LogFlushInterval, ses.LogSync)
+	if err != nil {
+		return nil, err
+	}
+
+	executor.logWriter = w
+
+	return w, nil
+}
+
+// closeLogWriter flushes and closes the log writer if it is open.
+func (executor *Executor) closeLogWriter() {
+	if executor.logWriter != nil {
+		_ = executor.logWriter.Close()
+		executor.logWriter = nil
+	}
+}
@@ -0,0 +1,148 @@
+package executor_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/crasssr/rcon-cli/internal/logger"
+	"github.com/gorcon/rcon"
+	"github.com/gorcon/rcon/rcontest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecutor_Results(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	t.Run("no errors", func(t *testing.T) {
+		app := executor.NewExecutor(nil, nil, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password"}
+
+		results, err := app.Results(ses, "help", "list")
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+		assert.Equal(t, "help", results[0].Command)
+		assert.Equal(t, "Can I help you?", results[0].Response)
+		assert.Equal(t, "unknown command", results[1].Response)
+		assert.Positive(t, results[0].Duration)
+	})
+
+	// Execution stops on the first error unless errors are skipped.
+	t.Run("empty command", func(t *testing.T) {
+		app := executor.NewExecutor(nil, nil, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password"}
+
+		results, err := app.Results(ses, "", "help")
+		assert.ErrorIs(t, err, executor.ErrCommandEmpty)
+		assert.Len(t, results, 1)
+
+		ses.SkipErrors = true
+
+		results, err = app.Results(ses, "", "help")
+		assert.NoError(t, err)
+		assert.Len(t, results, 2)
+		assert.ErrorIs(t, results[0].Err, executor.ErrCommandEmpty)
+		assert.Equal(t, "Can I help you?", results[1].Response)
+	})
+
+	// Test steps of chained commands are separate results.
+	t.Run("chaining", func(t *testing.T) {
+		app := executor.NewExecutor(nil, nil, "")
+		defer app.Close()
+
+		ses := &config.Session{Address: serverRCON.Addr(), Password: "password", Chaining: true}
+
+		results, err := app.Results(ses, "help && list", "help")
+		assert.NoError(t, err)
+		assert.Len(t, results, 3)
+		assert.Equal(t, "list", results[1].Command)
+		assert.Equal(t, "unknown command", results[1].Response)
+	})
+
+	t.Run("dial error", func(t *testing.T) {
+		app := executor.NewExecutor(nil, nil, "")
+		defer app.Close()
+
+		results, err := app.Results(&config.Session{Address: serverRCON.Addr(), Password: "wrong"}, "help")
+		assert.EqualError(t, err, "execute: auth: rcon: authentication failed")
+		assert.Empty(t, results)
+	})
+}
+
+func TestExecute_LogFormat(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(func(c *rcontest.Context) {
+			response := "unknown command"
+
+			switch c.Request().Body() {
+			case "binary":
+				response = "ab\xff\x01cd"
+			case "colored":
+				response = "§aSteve joined"
+			}
+
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, response).WriteTo(c.Conn())
+		}),
+	)
+	defer serverRCON.Close()
+
+	// Test the JSON log keeps bytes of the response received by Execute.
+	t.Run("json raw response", func(t *testing.T) {
+		logFileName := filepath.Join(t.TempDir(), "rcon.log")
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{
+			Address: serverRCON.Addr(), Password: "password", Log: logFileName, LogFormat: logger.FormatJSON,
+		}
+
+		err := app.Execute(&w, ses, "binary")
+		assert.NoError(t, err)
+
+		data, err := os.ReadFile(logFileName)
+		assert.NoError(t, err)
+
+		var entry logger.JSONEntry
+		assert.NoError(t, json.Unmarshal(data, &entry))
+		assert.Equal(t, "YWL/AWNk", entry.Response)
+		assert.Equal(t, logger.EncodingBase64, entry.Encoding)
+	})
+	// Test the HTML log converts color codes of the response regardless of
+	// the terminal color mode.
+	t.Run("html color codes", func(t *testing.T) {
+		for _, ses := range []config.Session{{NoColor: true}, {ColorMode: "256", ForceColor: true}} {
+			logFileName := filepath.Join(t.TempDir(), "rcon.html")
+
+			w := bytes.Buffer{}
+
+			app := executor.NewExecutor(nil, &w, "")
+
+			ses.Address, ses.Password, ses.Log, ses.LogFormat = serverRCON.Addr(), "password", logFileName, logger.FormatHTML
+
+			err := app.Execute(&w, &ses, "colored")
+			assert.NoError(t, err)
+			assert.NoError(t, app.Close())
+
+			data, err := os.ReadFile(logFileName)
+			assert.NoError(t, err)
+			assert.Contains(t, string(data), `<pre><span style="color:#55FF55">Steve joined</span></pre>`)
+			assert.NotContains(t, string(data), "\033[")
+		}
+	})
+}
@@ -0,0 +1,165 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// DefaultSSHPort is used when SSH target has no port.
+const DefaultSSHPort = "22"
+
+var (
+	// ErrInvalidSSHTarget is returned when SSH target is not in
+	// user@host[:port] form.
+	ErrInvalidSSHTarget = errors.New("invalid ssh target")
+
+	// ErrSSHNoAuth is returned when neither SSH key nor SSH agent is
+	// available.
+	ErrSSHNoAuth = errors.New("ssh authentication is not set: to set key add --ssh-key or start ssh-agent")
+)
+
+// ValidateSSH returns an error if the session SSH target can't be parsed.
+func ValidateSSH(ses *config.Session) error {
+	if ses.SSH == "" {
+		return nil
+	}
+
+	_, _, err := parseSSHTarget(ses.SSH)
+
+	return err
+}
+
+// parseSSHTarget splits SSH target user@host[:port] to the user and
+// the address with port.
+func parseSSHTarget(target string) (string, string, error) {
+	user, host, ok := strings.Cut(target, "@")
+	if !ok || user == "" || host == "" {
+		return "", "", fmt.Errorf("%w %q: expected user@host[:port]", ErrInvalidSSHTarget, target)
+	}
+
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, DefaultSSHPort)
+	}
+
+	return user, host, nil
+}
+
+// sshDialer returns the function dialing addresses through the SSH server
+// of the session. The SSH connection is opened with dial and reused for
+// the following connections of the executor.
+func (executor *Executor) sshDialer(ses *config.Session, dial dialFunc) (dialFunc, error) {
+	if executor.sshClient != nil && executor.sshTarget == ses.SSH {
+		return executor.sshClient.Dial, nil
+	}
+
+	executor.closeSSH()
+
+	user, address, err := parseSSHTarget(ses.SSH)
+	if err != nil {
+		return nil, err
+	}
+
+	hostKeyCallback, err := sshHostKeyCallback(ses)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, closeAuth, err := sshAuth(ses)
+	if err != nil {
+		return nil, err
+	}
+	defer closeAuth()
+
+	cfg := &ssh.ClientConfig{
+		User:            user,
+		Auth:            []ssh.AuthMethod{auth},
+		HostKeyCallback: hostKeyCallback,
+		Timeout:         ses.Timeout,
+	}
+
+	conn, err := dial("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("ssh: %w", err)
+	}
+
+	c, chans, reqs, err := ssh.NewClientConn(conn, address, cfg)
+	if err != nil {
+		_ = conn.Close()
+
+		return nil, fmt.Errorf("ssh: %w", err)
+	}
+
+	executor.sshClient = ssh.NewClient(c, chans, reqs)
+	executor.sshTarget = ses.SSH
+
+	return executor.sshClient.Dial, nil
+}
+
+// closeSSH closes the SSH connection if it is open.
+func (executor *Executor) closeSSH() {
+	if executor.sshClient != nil {
+		_ = executor.sshClient.Close()
+		executor.sshClient = nil
+	}
+}
+
+// sshAuth returns the auth method with the session SSH key or with keys of
+// SSH agent if the key is not set. The returned function closes the agent
+// connection after the handshake.
+func sshAuth(ses *config.Session) (ssh.AuthMethod, func(), error) {
+	if ses.SSHKey != "" {
+		data, err := os.ReadFile(ses.SSHKey)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ssh: read key: %w", err)
+		}
+
+		signer, err := ssh.ParsePrivateKey(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ssh: parse key: %w", err)
+		}
+
+		return ssh.PublicKeys(signer), func() {}, nil
+	}
+
+	socket := os.Getenv("SSH_AUTH_SOCK")
+	if socket == "" {
+		return nil, nil, ErrSSHNoAuth
+	}
+
+	conn, err := net.Dial("unix", socket)
+	if err != nil {
+		return nil, nil, fmt.Errorf("ssh: agent: %w", err)
+	}
+
+	return ssh.PublicKeysCallback(agent.NewClient(conn).Signers), func() { _ = conn.Close() }, nil
+}
+
+// sshHostKeyCallback verifies the server host key against the session
+// known hosts file or ~/.ssh/known_hosts.
+func sshHostKeyCallback(ses *config.Session) (ssh.HostKeyCallback, error) {
+	name := ses.SSHKnownHosts
+	if name == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("ssh: known hosts: %w", err)
+		}
+
+		name = filepath.Join(home, ".ssh", "known_hosts")
+	}
+
+	callback, err := knownhosts.New(name)
+	if err != nil {
+		return nil, fmt.Errorf("ssh: known hosts: %w", err)
+	}
+
+	return callback, nil
+}
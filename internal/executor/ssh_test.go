@@ -0,0 +1,199 @@
+package executor_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/gorcon/rcon/rcontest"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// newSSHServer starts SSH server forwarding direct-tcpip channels for
+// the authorized key. It returns the server address and the host key.
+func newSSHServer(t *testing.T, authorized ssh.PublicKey) (string, ssh.PublicKey) {
+	t.Helper()
+
+	_, hostKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hostSigner, err := ssh.NewSignerFromKey(hostKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &ssh.ServerConfig{
+		PublicKeyCallback: func(_ ssh.ConnMetadata, key ssh.PublicKey) (*ssh.Permissions, error) {
+			if !bytes.Equal(key.Marshal(), authorized.Marshal()) {
+				return nil, io.EOF
+			}
+
+			return &ssh.Permissions{}, nil
+		},
+	}
+	cfg.AddHostKey(hostSigner)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go serveSSH(conn, cfg)
+		}
+	}()
+
+	return listener.Addr().String(), hostSigner.PublicKey()
+}
+
+// serveSSH forwards direct-tcpip channels of the SSH connection.
+func serveSSH(conn net.Conn, cfg *ssh.ServerConfig) {
+	_, chans, reqs, err := ssh.NewServerConn(conn, cfg)
+	if err != nil {
+		return
+	}
+
+	go ssh.DiscardRequests(reqs)
+
+	for newChannel := range chans {
+		var target struct {
+			Host       string
+			Port       uint32
+			OriginHost string
+			OriginPort uint32
+		}
+
+		if newChannel.ChannelType() != "direct-tcpip" || ssh.Unmarshal(newChannel.ExtraData(), &target) != nil {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unsupported")
+
+			continue
+		}
+
+		remote, err := net.Dial("tcp", net.JoinHostPort(target.Host, strconv.Itoa(int(target.Port))))
+		if err != nil {
+			_ = newChannel.Reject(ssh.ConnectionFailed, err.Error())
+
+			continue
+		}
+
+		channel, channelReqs, err := newChannel.Accept()
+		if err != nil {
+			_ = remote.Close()
+
+			continue
+		}
+
+		go ssh.DiscardRequests(channelReqs)
+
+		go func() {
+			_, _ = io.Copy(remote, channel)
+			_ = remote.Close()
+		}()
+
+		go func() {
+			_, _ = io.Copy(channel, remote)
+			_ = channel.Close()
+		}()
+	}
+}
+
+func TestExecute_SSH(t *testing.T) {
+	server := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer server.Close()
+
+	_, clientKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clientSigner, err := ssh.NewSignerFromKey(clientKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block, err := ssh.MarshalPrivateKey(clientKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyFileName := "rcon-test-ssh-key"
+	createFile(keyFileName, string(pem.EncodeToMemory(block)))
+	defer os.Remove(keyFileName)
+
+	sshAddr, hostKey := newSSHServer(t, clientSigner.PublicKey())
+
+	knownHostsFileName := "rcon-test-known-hosts"
+	createFile(knownHostsFileName, knownhosts.Line([]string{sshAddr}, hostKey)+"\n")
+	defer os.Remove(knownHostsFileName)
+
+	// Test the connection is tunneled through SSH server.
+	t.Run("tunnel", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{
+			Address:       server.Addr(),
+			Password:      "password",
+			SSH:           "admin@" + sshAddr,
+			SSHKey:        keyFileName,
+			SSHKnownHosts: knownHostsFileName,
+		}
+
+		err := app.Execute(&w, ses, "help")
+		assert.NoError(t, err)
+		assert.Equal(t, "Can I help you?\n", w.String())
+	})
+
+	// Test unknown SSH server key is refused.
+	t.Run("unknown host", func(t *testing.T) {
+		emptyFileName := "rcon-test-known-hosts-empty"
+		createFile(emptyFileName, "")
+		defer os.Remove(emptyFileName)
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{
+			Address:       server.Addr(),
+			Password:      "password",
+			SSH:           "admin@" + sshAddr,
+			SSHKey:        keyFileName,
+			SSHKnownHosts: emptyFileName,
+		}
+
+		err := app.Execute(&w, ses, "help")
+		assert.ErrorContains(t, err, "knownhosts: key is unknown")
+	})
+
+	t.Run("invalid target", func(t *testing.T) {
+		err := executor.ValidateSSH(&config.Session{SSH: sshAddr})
+		assert.ErrorIs(t, err, executor.ErrInvalidSSHTarget)
+	})
+}
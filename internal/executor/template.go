@@ -0,0 +1,70 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrInvalidVariable is returned when --var value is not in key=value
+	// format.
+	ErrInvalidVariable = errors.New("invalid variable")
+
+	// ErrUnsetVariable is returned when a command contains a placeholder
+	// without the variable.
+	ErrUnsetVariable = errors.New("variable is not set")
+)
+
+// placeholderRegexp matches {key} placeholders. Keys are identifiers, so
+// JSON objects in commands are not treated as placeholders.
+var placeholderRegexp = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ParseVars parses key=value pairs to variables.
+func ParseVars(values []string) (map[string]string, error) {
+	vars := make(map[string]string, len(values))
+
+	for _, value := range values {
+		key, val, ok := strings.Cut(value, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%w %q: expected key=value", ErrInvalidVariable, value)
+		}
+
+		vars[key] = val
+	}
+
+	return vars, nil
+}
+
+// ApplyVars substitutes {key} placeholders in commands with variables.
+// Placeholders without variables are left as is if allowUnset is true and
+// cause ErrUnsetVariable otherwise.
+func ApplyVars(commands []Command, vars map[string]string, allowUnset bool) ([]Command, error) {
+	res := make([]Command, len(commands))
+
+	for i, command := range commands {
+		var err error
+
+		command.Text = placeholderRegexp.ReplaceAllStringFunc(command.Text, func(placeholder string) string {
+			key := placeholder[1 : len(placeholder)-1]
+			if val, ok := vars[key]; ok {
+				return val
+			}
+
+			if !allowUnset && err == nil {
+				err = fmt.Errorf("%w: %s in command %q", ErrUnsetVariable, key, command.Text)
+			}
+
+			return placeholder
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		res[i] = command
+	}
+
+	return res, nil
+}
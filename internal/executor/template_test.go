@@ -0,0 +1,50 @@
+package executor_test
+
+import (
+	"testing"
+
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVars(t *testing.T) {
+	vars, err := executor.ParseVars([]string{"player=Steve", "reason=afk=1", "empty="})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"player": "Steve", "reason": "afk=1", "empty": ""}, vars)
+
+	_, err = executor.ParseVars([]string{"player"})
+	assert.ErrorIs(t, err, executor.ErrInvalidVariable)
+
+	_, err = executor.ParseVars([]string{"=Steve"})
+	assert.ErrorIs(t, err, executor.ErrInvalidVariable)
+}
+
+func TestApplyVars(t *testing.T) {
+	vars := map[string]string{"player": "Steve", "reason": "afk"}
+
+	t.Run("substitute", func(t *testing.T) {
+		commands := []executor.Command{
+			{Text: "kick {player} {reason}"},
+			{Text: `/c game.print({"{player}"})`, Output: "out.txt"},
+		}
+
+		res, err := executor.ApplyVars(commands, vars, false)
+		assert.NoError(t, err)
+		assert.Equal(t, []executor.Command{
+			{Text: "kick Steve afk"},
+			{Text: `/c game.print({"Steve"})`, Output: "out.txt"},
+		}, res)
+		assert.Equal(t, "kick {player} {reason}", commands[0].Text)
+	})
+
+	t.Run("unset", func(t *testing.T) {
+		commands := executor.NewCommands("ban {player} {days}")
+
+		_, err := executor.ApplyVars(commands, vars, false)
+		assert.ErrorIs(t, err, executor.ErrUnsetVariable)
+
+		res, err := executor.ApplyVars(commands, vars, true)
+		assert.NoError(t, err)
+		assert.Equal(t, "ban Steve {days}", res[0].Text)
+	})
+}
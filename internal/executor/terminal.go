@@ -0,0 +1,165 @@
+package executor
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/crasssr/rcon-cli/internal/colors"
+	"github.com/crasssr/rcon-cli/internal/config"
+	"golang.org/x/term"
+)
+
+var (
+	// ErrNotConfirmed is returned when user declined the execution.
+	ErrNotConfirmed = errors.New("execution is not confirmed")
+
+	// ErrUnsupportedColorMode is returned when color mode is not one of
+	// the supported modes.
+	ErrUnsupportedColorMode = errors.New("unsupported color mode")
+)
+
+// isTerminal reports whether the reader or writer is an interactive
+// terminal. Other character devices like /dev/null are not terminals.
+func isTerminal(v interface{}) bool {
+	file, ok := v.(*os.File)
+
+	return ok && term.IsTerminal(int(file.Fd()))
+}
+
+// supportsColor reports whether w is a terminal capable of colors. Colors
+// are disabled by non-empty NO_COLOR environment variable and dumb TERM.
+func supportsColor(w interface{}) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	return isTerminal(w)
+}
+
+// ValidateColorMode returns an error if color mode is not supported.
+// Empty mode is detected from the terminal.
+func ValidateColorMode(mode string) error {
+	switch mode {
+	case "", colors.Mode16, colors.Mode256, colors.ModeTrueColor, colors.ModeNone:
+		return nil
+	default:
+		return fmt.Errorf("%w %q: allowed %q, %q, %q and %q", ErrUnsupportedColorMode, mode,
+			colors.Mode16, colors.Mode256, colors.ModeTrueColor, colors.ModeNone)
+	}
+}
+
+// terminalColorMode returns the color mode advertised by COLORTERM and
+// TERM environment variables.
+func terminalColorMode() string {
+	switch colorterm := os.Getenv("COLORTERM"); colorterm {
+	case "truecolor", "24bit":
+		return colors.ModeTrueColor
+	}
+
+	if strings.Contains(os.Getenv("TERM"), "256color") {
+		return colors.Mode256
+	}
+
+	return colors.Mode16
+}
+
+// colorMode returns the color mode used to print responses.
+func colorMode(ses *config.Session) string {
+	switch {
+	case ses.NoColor:
+		return colors.ModeNone
+	case ses.ColorMode == "":
+		return colors.Mode16
+	default:
+		return ses.ColorMode
+	}
+}
+
+// detectColor disables colors if responses are redirected to a file or
+// pipe unless colors are forced. If color mode is not set, it is detected
+// from the terminal.
+func (executor *Executor) detectColor(ses *config.Session) {
+	if ses.ColorMode == colors.ModeNone {
+		ses.NoColor = true
+	}
+
+	if !ses.ForceColor && !supportsColor(executor.responseWriter(ses)) {
+		ses.NoColor = true
+	}
+
+	if ses.ColorMode == "" {
+		ses.ColorMode = terminalColorMode()
+	}
+}
+
+// confirmTarget prints the resolved connection target. If the input is
+// an interactive terminal, it asks for confirmation before executing.
+func (executor *Executor) confirmTarget(ses *config.Session, env string) error {
+	protocol := ses.Type
+	if protocol == "" {
+		protocol = config.DefaultProtocol
+	}
+
+	_, _ = fmt.Fprintf(executor.w, "Target: %s (env: %s, type: %s)\n", ses.Address, env, protocol)
+
+	if !isTerminal(executor.r) {
+		return nil
+	}
+
+	_, _ = fmt.Fprint(executor.w, "Continue? [y/N]: ")
+
+	var answer string
+	_, _ = fmt.Fscanln(executor.r, &answer)
+
+	if !isConfirmed(answer) {
+		return ErrNotConfirmed
+	}
+
+	return nil
+}
+
+// promptSession asks for the missing address, password and type in
+// terminal. The password is read without echo. If the input is not
+// a terminal, missing address and password are errors and empty type is
+// the default protocol.
+func promptSession(r io.Reader, w io.Writer, ses *config.Session) error {
+	terminal := isTerminal(r)
+
+	if ses.Address == "" {
+		if !terminal {
+			return ErrEmptyAddress
+		}
+
+		_, _ = fmt.Fprint(w, "Enter remote host and port [ip:port]: ")
+		_, _ = fmt.Fscanln(r, &ses.Address)
+	}
+
+	if ses.Password == "" && !ses.NoAuth {
+		if !terminal {
+			return ErrEmptyPassword
+		}
+
+		_, _ = fmt.Fprint(w, "Enter password: ")
+
+		password, err := term.ReadPassword(int(r.(*os.File).Fd()))
+
+		// The newline typed by user is not echoed.
+		_, _ = fmt.Fprintln(w)
+
+		if err != nil {
+			return fmt.Errorf("read password: %w", err)
+		}
+
+		ses.Password = string(password)
+	}
+
+	if ses.Type == "" && terminal {
+		_, _ = fmt.Fprint(w, "Enter protocol type (empty for rcon): ")
+		_, _ = fmt.Fscanln(r, &ses.Type)
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package executor
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+)
+
+// ErrTLSCA is returned when the CA file of the session contains no
+// certificates.
+var ErrTLSCA = errors.New("no certificates in tls ca file")
+
+// tlsDialer returns the function dialing TLS connections over dial. The
+// handshake is finished within the session timeout, so the tunnel forwards
+// the decrypted stream to the client library.
+func tlsDialer(ses *config.Session, dial dialFunc) (dialFunc, error) {
+	host, _, err := net.SplitHostPort(ses.Address)
+	if err != nil {
+		return nil, fmt.Errorf("tls: %w", err)
+	}
+
+	cfg := &tls.Config{
+		ServerName:         host,
+		InsecureSkipVerify: ses.TLSInsecure,
+		MinVersion:         tls.VersionTLS12,
+	}
+
+	if ses.TLSCA != "" {
+		pem, err := os.ReadFile(ses.TLSCA)
+		if err != nil {
+			return nil, fmt.Errorf("tls: %w", err)
+		}
+
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("tls: %s: %w", ses.TLSCA, ErrTLSCA)
+		}
+	}
+
+	return func(network, address string) (net.Conn, error) {
+		conn, err := dial(network, address)
+		if err != nil {
+			return nil, err
+		}
+
+		if ses.Timeout > 0 {
+			_ = conn.SetDeadline(time.Now().Add(ses.Timeout))
+		}
+
+		client := tls.Client(conn, cfg)
+		if err = client.Handshake(); err != nil {
+			_ = conn.Close()
+
+			return nil, fmt.Errorf("tls: %w", err)
+		}
+
+		_ = conn.SetDeadline(time.Time{})
+
+		return client, nil
+	}, nil
+}
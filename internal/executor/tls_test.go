@@ -0,0 +1,176 @@
+package executor_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/gorcon/telnet/telnettest"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTLSServer starts TLS listener forwarding decrypted connections to the
+// target. It returns the listener address and the PEM file of its
+// self-signed certificate.
+func newTLSServer(t *testing.T, target string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ca := filepath.Join(t.TempDir(), "ca.pem")
+	if err = os.WriteFile(ca, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+
+				remote, err := net.Dial("tcp", target)
+				if err != nil {
+					return
+				}
+				defer remote.Close()
+
+				go func() {
+					_, _ = io.Copy(remote, conn)
+					_ = remote.Close()
+				}()
+
+				_, _ = io.Copy(conn, remote)
+			}()
+		}
+	}()
+
+	t.Cleanup(func() { _ = listener.Close() })
+
+	return listener.Addr().String(), ca
+}
+
+func TestExecutor_TELNETS(t *testing.T) {
+	serverTELNET := telnettest.NewServer(
+		telnettest.SetSettings(telnettest.Settings{Password: "password"}),
+		telnettest.SetCommandHandler(handlersTELNET),
+	)
+	defer serverTELNET.Close()
+
+	address, ca := newTLSServer(t, serverTELNET.Addr())
+
+	// Test the server certificate is verified with the CA file.
+	t.Run("tls ca", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{
+			Address: address, Password: "password", Type: config.ProtocolTELNETS, TLSCA: ca, Timeout: time.Second,
+		}
+
+		err := app.Execute(&w, ses, "help")
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "Can I help you?")
+	})
+
+	// Test verification is skipped with insecure flag.
+	t.Run("tls insecure", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{
+			Address: address, Password: "password", Type: config.ProtocolTELNETS, TLSInsecure: true,
+			Timeout: time.Second,
+		}
+
+		err := app.Execute(&w, ses, "help")
+		assert.NoError(t, err)
+		assert.Contains(t, w.String(), "Can I help you?")
+	})
+
+	// Test unknown certificate authority is refused.
+	t.Run("unknown authority", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{
+			Address: address, Password: "password", Type: config.ProtocolTELNETS, Timeout: time.Second,
+		}
+
+		err := app.Execute(&w, ses, "help")
+
+		var unknown x509.UnknownAuthorityError
+		assert.ErrorAs(t, err, &unknown)
+	})
+
+	// Test CA file without certificates.
+	t.Run("empty ca", func(t *testing.T) {
+		name := filepath.Join(t.TempDir(), "ca.pem")
+		if err := createFile(name, "not a certificate"); err != nil {
+			t.Fatal(err)
+		}
+
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ses := &config.Session{
+			Address: address, Password: "password", Type: config.ProtocolTELNETS, TLSCA: name, Timeout: time.Second,
+		}
+
+		err := app.Execute(&w, ses, "help")
+		assert.ErrorIs(t, err, executor.ErrTLSCA)
+	})
+}
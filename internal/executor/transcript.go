@@ -0,0 +1,49 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/crasssr/rcon-cli/internal/logger"
+)
+
+// transcript records commands and everything printed in response to them
+// during interactive session.
+type transcript struct {
+	address string
+	started time.Time
+	entries strings.Builder
+}
+
+// newTranscript creates transcript of the session with the address.
+func newTranscript(address string) *transcript {
+	return &transcript{address: address, started: time.Now()}
+}
+
+// Writer returns writer which copies command output to w and to the
+// transcript entry of the command.
+func (t *transcript) Writer(w io.Writer, command string) io.Writer {
+	_, _ = fmt.Fprintf(&t.entries, "[%s] > %s\n", time.Now().Format(logger.DefaultTimeLayout), command)
+
+	return io.MultiWriter(w, &t.entries)
+}
+
+// Save writes the transcript with the session header to the file.
+func (t *transcript) Save(name string) error {
+	var text strings.Builder
+
+	_, _ = fmt.Fprintf(&text, "Session with %s started at %s\n\n", t.address, t.started.Format(logger.DefaultTimeLayout))
+	text.WriteString(t.entries.String())
+	_, _ = fmt.Fprintf(&text, "\nSession ended at %s\n", time.Now().Format(logger.DefaultTimeLayout))
+
+	const perm = 0o666
+
+	if err := os.WriteFile(name, []byte(text.String()), perm); err != nil {
+		return fmt.Errorf("transcript: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package executor
+
+import (
+	"strings"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+)
+
+// DefaultFlattenSeparator is the separator of flattened response lines.
+const DefaultFlattenSeparator = " "
+
+// trimResponse removes the configured prefix and suffix decoration from
+// the response. With ses.PerLine the decoration is removed from each line.
+func trimResponse(ses *config.Session, response string) string {
+	if ses.TrimPrefix == "" && ses.TrimSuffix == "" {
+		return response
+	}
+
+	if !ses.PerLine {
+		return trim(response, ses.TrimPrefix, ses.TrimSuffix)
+	}
+
+	lines := strings.Split(response, "\n")
+	for i, line := range lines {
+		lines[i] = trim(strings.TrimSuffix(line, "\r"), ses.TrimPrefix, ses.TrimSuffix)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// stripEcho removes the first line of the response if it repeats the sent
+// command. The line must match exactly unless ses.StripEchoIgnoreCase is set.
+func stripEcho(ses *config.Session, command string, response string) string {
+	if !ses.StripEcho {
+		return response
+	}
+
+	first, rest, _ := strings.Cut(response, "\n")
+	first = strings.TrimSuffix(first, "\r")
+	command = strings.TrimSpace(command)
+
+	if first == command || (ses.StripEchoIgnoreCase && strings.EqualFold(first, command)) {
+		return strings.TrimLeft(rest, "\r\n")
+	}
+
+	return response
+}
+
+// trim removes prefix and suffix from text and the spaces they leave.
+func trim(text string, prefix string, suffix string) string {
+	if prefix != "" && strings.HasPrefix(text, prefix) {
+		text = strings.TrimLeft(strings.TrimPrefix(text, prefix), " ")
+	}
+
+	if suffix != "" && strings.HasSuffix(text, suffix) {
+		text = strings.TrimRight(strings.TrimSuffix(text, suffix), " ")
+	}
+
+	return text
+}
+
+// flatten joins lines of the response with the session separator when
+// ses.Flatten is set.
+func flatten(ses *config.Session, response string) string {
+	if !ses.Flatten {
+		return response
+	}
+
+	separator := ses.FlattenSeparator
+	if separator == "" {
+		separator = DefaultFlattenSeparator
+	}
+
+	return strings.Join(strings.Split(strings.ReplaceAll(response, "\r\n", "\n"), "\n"), separator)
+}
@@ -0,0 +1,93 @@
+package executor_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/gorcon/rcon"
+	"github.com/gorcon/rcon/rcontest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecute_StripEcho(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(func(c *rcontest.Context) {
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, "List\r\nThere are 0 players").WriteTo(c.Conn())
+		}),
+	)
+	defer serverRCON.Close()
+
+	tests := []struct {
+		name     string
+		ses      config.Session
+		command  string
+		expected string
+	}{
+		{"no strip", config.Session{}, "List", "List\r\nThere are 0 players\n"},
+		{"exact", config.Session{StripEcho: true}, "List", "There are 0 players\n"},
+		{"case-sensitive", config.Session{StripEcho: true}, "list", "List\r\nThere are 0 players\n"},
+		{"ignore case", config.Session{StripEcho: true, StripEchoIgnoreCase: true}, "list", "There are 0 players\n"},
+		{"not whole line", config.Session{StripEcho: true}, "Li", "List\r\nThere are 0 players\n"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			w := bytes.Buffer{}
+
+			app := executor.NewExecutor(nil, &w, "")
+			defer app.Close()
+
+			tt.ses.Address = serverRCON.Addr()
+			tt.ses.Password = "password"
+
+			err := app.Execute(&w, &tt.ses, tt.command)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, w.String())
+		})
+	}
+}
+
+func TestExecute_Trim(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(func(c *rcontest.Context) {
+			rcon.NewPacket(rcon.SERVERDATA_RESPONSE_VALUE, c.Request().ID, "[Server] one;\n[Server] two;").WriteTo(c.Conn())
+		}),
+	)
+	defer serverRCON.Close()
+
+	tests := []struct {
+		name     string
+		ses      config.Session
+		expected string
+	}{
+		{"no trim", config.Session{}, "[Server] one;\n[Server] two;\n"},
+		{"whole response", config.Session{TrimPrefix: "[Server]", TrimSuffix: ";"}, "one;\n[Server] two\n"},
+		{"per line", config.Session{TrimPrefix: "[Server]", TrimSuffix: ";", PerLine: true}, "one\ntwo\n"},
+		{"flatten", config.Session{Flatten: true}, "[Server] one; [Server] two;\n"},
+		{"flatten separator", config.Session{Flatten: true, FlattenSeparator: " | ", TrimPrefix: "[Server]", PerLine: true}, "one; | two;\n"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			w := bytes.Buffer{}
+
+			app := executor.NewExecutor(nil, &w, "")
+			defer app.Close()
+
+			tt.ses.Address = serverRCON.Addr()
+			tt.ses.Password = "password"
+
+			err := app.Execute(&w, &tt.ses, "list")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, w.String())
+		})
+	}
+}
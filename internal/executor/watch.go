@@ -0,0 +1,140 @@
+package executor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/urfave/cli/v2"
+)
+
+// Watch defaults and controls.
+const (
+	DefaultWatchInterval = 2 * time.Second
+
+	// WatchQuit is the input line to exit the watch mode.
+	WatchQuit = "q"
+
+	// clearScreen moves the cursor home and clears the terminal.
+	clearScreen = "\033[H\033[2J"
+)
+
+// Watch executes the command every interval and redraws the latest response
+// on the cleared screen with the timestamp and the last change indicator.
+// It stops when ctx is done or the quit line is read from r.
+func (executor *Executor) Watch(ctx context.Context, r io.Reader, w io.Writer, ses *config.Session,
+	command string, interval time.Duration,
+) error {
+	if command == "" {
+		return ErrCommandEmpty
+	}
+
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if r != nil {
+		go func() {
+			scanner := bufio.NewScanner(r)
+			for scanner.Scan() {
+				if strings.TrimSpace(scanner.Text()) == WatchQuit {
+					cancel()
+
+					return
+				}
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var (
+		last       string
+		lastChange time.Time
+	)
+
+	for first := true; ; first = false {
+		var buffer bytes.Buffer
+		if err := executor.ExecuteCommands(&buffer, ses, NewCommands(command)...); err != nil {
+			return err
+		}
+
+		now := time.Now()
+
+		response := buffer.String()
+		if first || response != last {
+			last = response
+			lastChange = now
+		}
+
+		changed := "changed " + now.Sub(lastChange).Round(time.Second).String() + " ago"
+		if lastChange.Equal(now) {
+			changed = "changed now"
+		}
+
+		_, _ = fmt.Fprintf(w, "%sEvery %s: %s\t%s (%s, type %s to exit)\n\n%s",
+			clearScreen, interval, command, now.Format(time.RFC3339), changed, WatchQuit, response)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// watchCommand creates the watch subcommand.
+func (executor *Executor) watchCommand() *cli.Command {
+	return &cli.Command{
+		Name:      "watch",
+		Usage:     "Redraw the command response on the cleared screen every interval",
+		UsageText: "watch --command list --interval 2s",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "command",
+				Usage:    "Command to send",
+				Required: true,
+			},
+			&cli.DurationFlag{
+				Name:  "interval",
+				Usage: "Interval between redraws",
+				Value: DefaultWatchInterval,
+			},
+		},
+		Action: executor.watch,
+	}
+}
+
+// watch executes the watch subcommand until q or SIGINT.
+func (executor *Executor) watch(c *cli.Context) error {
+	ses, err := executor.NewSession(c)
+	if err != nil {
+		return err
+	}
+
+	if ses.Address == "" {
+		return ErrEmptyAddress
+	}
+
+	if ses.Password == "" && !ses.NoAuth {
+		return ErrEmptyPassword
+	}
+
+	executor.detectColor(ses)
+
+	ctx, stop := signal.NotifyContext(c.Context, os.Interrupt)
+	defer stop()
+
+	return executor.Watch(ctx, executor.r, executor.w, ses, c.String("command"), c.Duration("interval"))
+}
@@ -0,0 +1,56 @@
+package executor_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/executor"
+	"github.com/gorcon/rcon/rcontest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecutor_Watch(t *testing.T) {
+	serverRCON := rcontest.NewServer(
+		rcontest.SetSettings(rcontest.Settings{Password: "password"}),
+		rcontest.SetCommandHandler(handlersRCON),
+	)
+	defer serverRCON.Close()
+
+	ses := &config.Session{Address: serverRCON.Addr(), Password: "password"}
+
+	// Test screen is redrawn until context is done.
+	t.Run("redraw", func(t *testing.T) {
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(nil, &w, "")
+		defer app.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+		defer cancel()
+
+		err := app.Watch(ctx, nil, &w, ses, "help", 100*time.Millisecond)
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, strings.Count(w.String(), "\033[H\033[2J"), 2)
+		assert.Contains(t, w.String(), "Every 100ms: help")
+		assert.Contains(t, w.String(), "Can I help you?")
+	})
+
+	// Test quit from input.
+	t.Run("quit", func(t *testing.T) {
+		r := bytes.NewBufferString(executor.WatchQuit + "\n")
+		w := bytes.Buffer{}
+
+		app := executor.NewExecutor(r, &w, "")
+		defer app.Close()
+
+		start := time.Now()
+
+		err := app.Watch(context.Background(), r, &w, ses, "help", time.Minute)
+		assert.NoError(t, err)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
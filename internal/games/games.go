@@ -0,0 +1,200 @@
+// Package games contains presets of game servers selected with --game flag.
+// A preset configures the protocol, default port, command handling and
+// completion of the game at once.
+package games
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+)
+
+// Games with presets.
+const (
+	SevenDaysToDie = "7dtd"
+	ARK            = "ark"
+	Factorio       = "factorio"
+	Minecraft      = "minecraft"
+	Palworld       = "palworld"
+	Rust           = "rust"
+	Zomboid        = "zomboid"
+)
+
+// Preset contains the game specific defaults. Flags and config fields take
+// precedence over them.
+type Preset struct {
+	// Type is the protocol of the game server.
+	Type string
+	// DefaultPort is used when the address has no port.
+	DefaultPort string
+	// StripLeadingSlash removes the leading slash of commands copied from
+	// in-game chat.
+	StripLeadingSlash bool
+	// ErrorPatterns match responses of failed commands. The server reports
+	// errors in the response body, the protocol has no error status.
+	ErrorPatterns []*regexp.Regexp
+	// Commands are the known commands of the game offered for completion.
+	Commands []string
+	// HelpCommand lists the commands of the server. HelpPattern captures
+	// command names in its output, so commands of mods and plugins are
+	// completed too.
+	HelpCommand string
+	HelpPattern *regexp.Regexp
+}
+
+// presets contains presets per game.
+var presets = map[string]Preset{
+	SevenDaysToDie: {
+		Type:        config.ProtocolTELNET,
+		DefaultPort: "8081",
+		Commands: []string{
+			"admin", "ban", "gettime", "help", "kick", "listplayers", "saveworld", "say", "settime", "shutdown",
+			"version", "whitelist",
+		},
+		// Lines like ` admin => Manage user permission levels`, aliases
+		// follow the name.
+		HelpCommand: "help",
+		HelpPattern: regexp.MustCompile(`(?m)^\s*([\w.-]+)(?:\s+[\w.-]+)*\s+=>`),
+	},
+	ARK: {
+		Type:        config.ProtocolRCON,
+		DefaultPort: "27020",
+		Commands: []string{
+			"banplayer", "broadcast", "destroywilddinos", "doexit", "getchat", "kickplayer", "listplayers",
+			"saveworld", "serverchat", "settimeofday", "unbanplayer",
+		},
+	},
+	Factorio: {
+		Type:        config.ProtocolRCON,
+		DefaultPort: "27015",
+		// Inline Lua errors, e.g. `Cannot execute command. Error: [string "..."]:1: ...`.
+		ErrorPatterns: []*regexp.Regexp{
+			regexp.MustCompile(`(?m)^Cannot execute command\. Error: .*$`),
+			regexp.MustCompile(`(?m)^Unknown command ".*$`),
+		},
+		// Commands without slash are sent to the chat.
+		Commands: []string{
+			"/admins", "/ban", "/bans", "/command", "/demote", "/evolution", "/help", "/kick", "/mute",
+			"/players", "/promote", "/purge", "/seed", "/server-save", "/time", "/unban", "/unmute", "/version",
+			"/whitelist",
+		},
+		// Lines like `/admins - Prints a list of game admins.`.
+		HelpCommand: "/help",
+		HelpPattern: regexp.MustCompile(`(?m)^(/[\w-]+)`),
+	},
+	Minecraft: {
+		Type:              config.ProtocolRCON,
+		DefaultPort:       "25575",
+		StripLeadingSlash: true,
+		ErrorPatterns: []*regexp.Regexp{
+			regexp.MustCompile(`(?m)^Unknown (or incomplete )?command.*$`),
+			regexp.MustCompile(`(?m)^Incorrect argument for command.*$`),
+		},
+		Commands: []string{
+			"ban", "ban-ip", "banlist", "deop", "difficulty", "gamemode", "give", "help", "kick", "kill", "list",
+			"op", "pardon", "pardon-ip", "save-all", "save-off", "save-on", "say", "seed", "stop", "tell", "time",
+			"tp", "weather", "whitelist",
+		},
+		// Usages like `/ban <targets> [<reason>]`, the server joins them
+		// without line breaks.
+		HelpCommand: "help",
+		HelpPattern: regexp.MustCompile(`(?:^|[\s>\])])/([\w-]+)`),
+	},
+	Palworld: {
+		Type:        config.ProtocolRCON,
+		DefaultPort: "25575",
+		Commands: []string{
+			"Broadcast", "BanPlayer", "DoExit", "Info", "KickPlayer", "Save", "ShowPlayers", "Shutdown",
+			"UnBanPlayer",
+		},
+	},
+	Rust: {
+		Type:        config.ProtocolWebRCON,
+		DefaultPort: "28016",
+		Commands: []string{
+			"ban", "banid", "kick", "playerlist", "quit", "say", "server.save", "serverinfo", "status", "unban",
+			"users",
+		},
+	},
+	Zomboid: {
+		Type:        config.ProtocolRCON,
+		DefaultPort: "27015",
+		Commands: []string{
+			"additem", "adduser", "banuser", "help", "kickuser", "players", "quit", "save", "servermsg",
+			"setaccesslevel", "unbanuser",
+		},
+		// Lines like `* additem : Give an item to a player.`.
+		HelpCommand: "help",
+		HelpPattern: regexp.MustCompile(`(?m)^\*\s*([\w-]+)\s*:`),
+	},
+}
+
+// Get returns the preset of the game.
+func Get(name string) (Preset, bool) {
+	preset, ok := presets[name]
+
+	return preset, ok
+}
+
+// MatchError returns the first part of the response matching the error
+// patterns of the preset or empty string if the command succeeded.
+func (preset Preset) MatchError(response string) string {
+	for _, pattern := range preset.ErrorPatterns {
+		if match := pattern.FindString(response); match != "" {
+			return match
+		}
+	}
+
+	return ""
+}
+
+// ParseHelp returns the command names captured by the help pattern in
+// the help output of the server.
+func (preset Preset) ParseHelp(response string) []string {
+	if preset.HelpPattern == nil {
+		return nil
+	}
+
+	var commands []string
+
+	for _, match := range preset.HelpPattern.FindAllStringSubmatch(response, -1) {
+		commands = append(commands, match[1])
+	}
+
+	return commands
+}
+
+// Complete returns the sorted unique known commands of the preset and
+// the extra commands starting with the prefix. Letter case is ignored.
+func (preset Preset) Complete(prefix string, extra []string) []string {
+	seen := make(map[string]bool)
+
+	var commands []string
+
+	for _, command := range append(append([]string{}, preset.Commands...), extra...) {
+		if seen[command] || !strings.HasPrefix(strings.ToLower(command), strings.ToLower(prefix)) {
+			continue
+		}
+
+		seen[command] = true
+		commands = append(commands, command)
+	}
+
+	sort.Strings(commands)
+
+	return commands
+}
+
+// Names returns the sorted names of games with presets.
+func Names() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,65 @@
+package games_test
+
+import (
+	"testing"
+
+	"github.com/crasssr/rcon-cli/internal/config"
+	"github.com/crasssr/rcon-cli/internal/games"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	preset, ok := games.Get(games.Minecraft)
+	assert.True(t, ok)
+	assert.Equal(t, config.ProtocolRCON, preset.Type)
+	assert.Equal(t, "25575", preset.DefaultPort)
+	assert.True(t, preset.StripLeadingSlash)
+
+	_, ok = games.Get("pong")
+	assert.False(t, ok)
+}
+
+func TestNames(t *testing.T) {
+	assert.Equal(t, []string{"7dtd", "ark", "factorio", "minecraft", "palworld", "rust", "zomboid"}, games.Names())
+}
+
+func TestPreset_MatchError(t *testing.T) {
+	preset, _ := games.Get(games.Minecraft)
+
+	assert.Equal(t, "Unknown or incomplete command, see below for error",
+		preset.MatchError("Unknown or incomplete command, see below for error\nfoo<--[HERE]"))
+	assert.Equal(t, "Incorrect argument for command", preset.MatchError("Incorrect argument for command"))
+	assert.Empty(t, preset.MatchError("There are 0 of a max of 20 players online"))
+
+	// Presets without patterns match nothing.
+	preset, _ = games.Get(games.Rust)
+	assert.Empty(t, preset.MatchError("Unknown command"))
+}
+
+func TestPreset_ParseHelp(t *testing.T) {
+	preset, _ := games.Get(games.Minecraft)
+	assert.Equal(t, []string{"ban", "banlist", "mymod"},
+		preset.ParseHelp("/ban <targets> [<reason>]/banlist [ips|players]/mymod reload"))
+
+	preset, _ = games.Get(games.SevenDaysToDie)
+	assert.Equal(t, []string{"admin", "ban"},
+		preset.ParseHelp("*** List of Commands ***\n admin => Manage user permission levels\n ban => Manage ban entries\n"))
+
+	preset, _ = games.Get(games.Factorio)
+	assert.Equal(t, []string{"/admins", "/ban"},
+		preset.ParseHelp("Available commands:\n/admins - Prints a list of game admins.\n/ban <player> - Bans the player.\n"))
+
+	preset, _ = games.Get(games.Zomboid)
+	assert.Equal(t, []string{"additem"}, preset.ParseHelp("List of server commands :\n* additem : Give an item to a player.\n"))
+
+	// Presets without help command parse nothing.
+	preset, _ = games.Get(games.Rust)
+	assert.Empty(t, preset.ParseHelp("ban\nkick"))
+}
+
+func TestPreset_Complete(t *testing.T) {
+	preset, _ := games.Get(games.Minecraft)
+	assert.Equal(t, []string{"ban", "ban-ip", "banlist", "banmod"}, preset.Complete("BAN", []string{"banmod", "ban"}))
+	assert.Empty(t, preset.Complete("pong", nil))
+	assert.Len(t, preset.Complete("", nil), len(preset.Commands))
+}
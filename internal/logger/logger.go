@@ -1,11 +1,20 @@
 package logger
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"html"
 	"os"
 	"path/filepath"
+	"strings"
+	"text/template"
 	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/crasssr/rcon-cli/internal/colors"
 )
 
 // DefaultTimeLayout is layout for convert time.Now to String.
@@ -14,8 +23,53 @@ const DefaultTimeLayout = "2006-01-02 15:04:05"
 // DefaultLineFormat is format to log line record.
 const DefaultLineFormat = "[%s] %s: %s\n%s\n\n"
 
-// ErrEmptyFileName is returned when trying to open file with empty name.
-var ErrEmptyFileName = errors.New("empty file name")
+// HTMLLineFormat is format to log line record in HTML log format.
+const HTMLLineFormat = "<p>[%s] %s: %s</p>\n<pre>%s</pre>\n\n"
+
+// Supported log formats.
+const (
+	FormatText = "text"
+	FormatHTML = "html"
+	FormatJSON = "json"
+)
+
+// EncodingBase64 marks base64 encoded responses in JSON log format.
+const EncodingBase64 = "base64"
+
+var (
+	// ErrEmptyFileName is returned when trying to open file with empty name.
+	ErrEmptyFileName = errors.New("empty file name")
+
+	// ErrUnsupportedFormat is returned when log format is not one of
+	// the supported formats.
+	ErrUnsupportedFormat = errors.New("unsupported log format")
+
+	// ErrInvalidTemplate is returned when log template can't be parsed.
+	ErrInvalidTemplate = errors.New("invalid log template")
+
+	// ErrNoDirectory is returned when the directory of log file doesn't
+	// exist and creating of it is disabled.
+	ErrNoDirectory = errors.New("log directory does not exist")
+)
+
+// Entry is the log record passed to the log template.
+type Entry struct {
+	Timestamp string
+	Address   string
+	Type      string
+	Command   string
+	Response  string
+}
+
+// JSONEntry is the log record in JSON log format. Responses with
+// non-printable bytes are base64 encoded and marked with Encoding.
+type JSONEntry struct {
+	Timestamp string `json:"timestamp"`
+	Address   string `json:"address"`
+	Command   string `json:"command"`
+	Response  string `json:"response"`
+	Encoding  string `json:"encoding,omitempty"`
+}
 
 // OpenFile opens file for append strings. Creates file if file not exist.
 func OpenFile(name string) (*os.File, error) {
@@ -52,20 +106,158 @@ func OpenFile(name string) (*os.File, error) {
 	return file, nil
 }
 
+// CheckDir returns an error if the directory of log file doesn't exist.
+// It is used to opt out of creating missing directories by OpenFile.
+func CheckDir(name string) error {
+	if name == "" {
+		return nil
+	}
+
+	dir := filepath.Dir(name)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return fmt.Errorf("%w: %s", ErrNoDirectory, dir)
+	}
+
+	return nil
+}
+
+// ValidateFormat returns an error if log format is not supported. Empty
+// format is treated as text.
+func ValidateFormat(format string) error {
+	switch format {
+	case "", FormatText, FormatHTML, FormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("%w %q: allowed %q, %q and %q", ErrUnsupportedFormat, format, FormatText, FormatHTML, FormatJSON)
+	}
+}
+
 // Write saves request and response to log file.
 func Write(name string, address string, request string, response string) error {
+	return WriteFormat(name, FormatText, address, request, response)
+}
+
+// WriteFormat saves request and response to log file in the format. In HTML
+// format color codes of the response are converted to span elements. In JSON
+// format every record is a JSON object on its own line.
+func WriteFormat(name string, format string, address string, request string, response string) error {
+	// Disable logging if log file name is empty.
+	if name == "" {
+		return nil
+	}
+
+	line, err := FormatLine(format, address, request, response)
+	if err != nil {
+		return err
+	}
+
+	return AppendLine(name, line)
+}
+
+// FormatLine returns the log record of request and response in the format
+// of WriteFormat.
+func FormatLine(format string, address string, request string, response string) (string, error) {
+	now := time.Now().Format(DefaultTimeLayout)
+
+	switch format {
+	case FormatHTML:
+		return fmt.Sprintf(HTMLLineFormat, now, html.EscapeString(address), html.EscapeString(request),
+			colors.ToHTML(response)), nil
+	case FormatJSON:
+		js, err := json.Marshal(NewJSONEntry(now, address, request, response))
+		if err != nil {
+			return "", fmt.Errorf("marshal: %w", err)
+		}
+
+		return string(js) + "\n", nil
+	default:
+		return fmt.Sprintf(DefaultLineFormat, now, address, request, response), nil
+	}
+}
+
+// NewJSONEntry creates the JSON log record. The response is base64 encoded
+// if it is not printable text.
+func NewJSONEntry(timestamp string, address string, request string, response string) JSONEntry {
+	entry := JSONEntry{Timestamp: timestamp, Address: address, Command: request, Response: response}
+
+	if !isPrintable(response) {
+		entry.Response = base64.StdEncoding.EncodeToString([]byte(response))
+		entry.Encoding = EncodingBase64
+	}
+
+	return entry
+}
+
+// isPrintable reports whether text is valid UTF-8 without control
+// characters other than whitespace and escape of terminal colors.
+func isPrintable(text string) bool {
+	if !utf8.ValidString(text) {
+		return false
+	}
+
+	for _, r := range text {
+		if !unicode.IsPrint(r) && !unicode.IsSpace(r) && r != '\033' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ParseTemplate parses Go template of the log line. Fields of Entry are
+// available in the template, e.g. `{{.Timestamp}} {{.Command}}`.
+func ParseTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("log").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidTemplate, err)
+	}
+
+	return tmpl, nil
+}
+
+// WriteTemplate saves the entry to log file in the format of the template.
+// Timestamp of the entry is set to the current time. The line is terminated
+// with new line if the template doesn't end with it.
+func WriteTemplate(name string, tmpl *template.Template, entry Entry) error {
 	// Disable logging if log file name is empty.
 	if name == "" {
 		return nil
 	}
 
+	line, err := TemplateLine(tmpl, entry)
+	if err != nil {
+		return err
+	}
+
+	return AppendLine(name, line)
+}
+
+// TemplateLine returns the log record of the entry in the format of
+// WriteTemplate.
+func TemplateLine(tmpl *template.Template, entry Entry) (string, error) {
+	entry.Timestamp = time.Now().Format(DefaultTimeLayout)
+
+	var line strings.Builder
+	if err := tmpl.Execute(&line, entry); err != nil {
+		return "", fmt.Errorf("template: %w", err)
+	}
+
+	if !strings.HasSuffix(line.String(), "\n") {
+		line.WriteString("\n")
+	}
+
+	return line.String(), nil
+}
+
+// AppendLine appends the line to log file. The file is opened and closed
+// for every line.
+func AppendLine(name string, line string) error {
 	file, err := OpenFile(name)
 	if err != nil {
 		return err
 	}
 	defer file.Close()
 
-	line := fmt.Sprintf(DefaultLineFormat, time.Now().Format(DefaultTimeLayout), address, request, response)
 	if _, err = file.WriteString(line); err != nil {
 		return fmt.Errorf("write: %w", err)
 	}
@@ -1,7 +1,9 @@
 package logger_test
 
 import (
+	"encoding/json"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/crasssr/rcon-cli/internal/logger"
@@ -31,6 +33,12 @@ func TestOpenFile(t *testing.T) {
 	})
 }
 
+func TestCheckDir(t *testing.T) {
+	assert.NoError(t, logger.CheckDir(""))
+	assert.NoError(t, logger.CheckDir("tmpfile.log"))
+	assert.ErrorIs(t, logger.CheckDir("missing/tmpfile.log"), logger.ErrNoDirectory)
+}
+
 func TestWrite(t *testing.T) {
 	logName := "tmpfile.log"
 
@@ -60,3 +68,83 @@ func TestWrite(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestWriteFormat(t *testing.T) {
+	logName := "tmpfile.html"
+
+	defer os.Remove(logName)
+
+	// Test colors are converted to HTML.
+	t.Run("html", func(t *testing.T) {
+		err := logger.WriteFormat(logName, logger.FormatHTML, "127.0.0.1:16200", "list <all>", "§aSteve§r joined")
+		assert.NoError(t, err)
+
+		data, err := os.ReadFile(logName)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `127.0.0.1:16200: list &lt;all&gt;</p>`)
+		assert.Contains(t, string(data), `<pre><span style="color:#55FF55">Steve</span> joined</pre>`)
+	})
+
+	// Test binary responses are base64 encoded in JSON format.
+	t.Run("json", func(t *testing.T) {
+		jsonLogName := "tmpfile.json"
+		defer os.Remove(jsonLogName)
+
+		err := logger.WriteFormat(jsonLogName, logger.FormatJSON, "127.0.0.1:16200", "list", "Steve joined\n")
+		assert.NoError(t, err)
+
+		err = logger.WriteFormat(jsonLogName, logger.FormatJSON, "127.0.0.1:16200", "dump", "\x00\x01\xff")
+		assert.NoError(t, err)
+
+		data, err := os.ReadFile(jsonLogName)
+		assert.NoError(t, err)
+
+		lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+		assert.Len(t, lines, 2)
+
+		var entry logger.JSONEntry
+
+		assert.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+		assert.Equal(t, "Steve joined\n", entry.Response)
+		assert.Empty(t, entry.Encoding)
+
+		assert.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+		assert.Equal(t, "AAH/", entry.Response)
+		assert.Equal(t, logger.EncodingBase64, entry.Encoding)
+	})
+
+	// Test unsupported format.
+	t.Run("validate", func(t *testing.T) {
+		assert.NoError(t, logger.ValidateFormat(""))
+		assert.NoError(t, logger.ValidateFormat(logger.FormatHTML))
+		assert.NoError(t, logger.ValidateFormat(logger.FormatJSON))
+		assert.ErrorIs(t, logger.ValidateFormat("xml"), logger.ErrUnsupportedFormat)
+	})
+}
+
+func TestWriteTemplate(t *testing.T) {
+	logName := "tmpfile.log"
+
+	defer os.Remove(logName)
+
+	// Test entry fields are available in the template.
+	t.Run("custom format", func(t *testing.T) {
+		tmpl, err := logger.ParseTemplate("{{.Type}} {{.Address}} {{.Command}}={{.Response}}")
+		assert.NoError(t, err)
+
+		entry := logger.Entry{Address: "127.0.0.1:16200", Type: "rcon", Command: "list", Response: "Steve"}
+
+		err = logger.WriteTemplate(logName, tmpl, entry)
+		assert.NoError(t, err)
+
+		data, err := os.ReadFile(logName)
+		assert.NoError(t, err)
+		assert.Equal(t, "rcon 127.0.0.1:16200 list=Steve\n", string(data))
+	})
+
+	// Test invalid template.
+	t.Run("invalid template", func(t *testing.T) {
+		_, err := logger.ParseTemplate("{{.Command")
+		assert.ErrorIs(t, err, logger.ErrInvalidTemplate)
+	})
+}
@@ -0,0 +1,119 @@
+package logger
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// Writer keeps the log file open between records. Records are buffered and
+// flushed on the interval and on Close. In sync mode every record is
+// written and synced to disk at once.
+type Writer struct {
+	name string
+	sync bool
+
+	mu   sync.Mutex
+	file *os.File
+	buf  *bufio.Writer
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewWriter opens the log file for buffered writing. The buffer is flushed
+// every interval if it is positive. Missing directories are created like
+// in OpenFile.
+func NewWriter(name string, interval time.Duration, sync bool) (*Writer, error) {
+	file, err := OpenFile(name)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Writer{name: name, sync: sync, file: file, buf: bufio.NewWriter(file)}
+
+	if interval > 0 && !sync {
+		w.stop = make(chan struct{})
+		w.done = make(chan struct{})
+
+		go w.flushEvery(interval)
+	}
+
+	return w, nil
+}
+
+// Name returns the name of the log file.
+func (w *Writer) Name() string {
+	return w.name
+}
+
+// WriteLine writes the log record.
+func (w *Writer) WriteLine(line string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, err := w.buf.WriteString(line); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	if !w.sync {
+		return nil
+	}
+
+	if err := w.buf.Flush(); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	if err := w.file.Sync(); err != nil {
+		return fmt.Errorf("sync: %w", err)
+	}
+
+	return nil
+}
+
+// Flush writes buffered records to the file.
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.buf.Flush(); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+
+	return nil
+}
+
+// Close flushes buffered records and closes the file.
+func (w *Writer) Close() error {
+	if w.stop != nil {
+		close(w.stop)
+		<-w.done
+	}
+
+	err := w.Flush()
+
+	if closeErr := w.file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("close: %w", closeErr)
+	}
+
+	return err
+}
+
+// flushEvery flushes the buffer on the interval until Close.
+func (w *Writer) flushEvery(interval time.Duration) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			_ = w.Flush()
+		case <-w.stop:
+			return
+		}
+	}
+}
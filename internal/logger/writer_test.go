@@ -0,0 +1,73 @@
+package logger_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/crasssr/rcon-cli/internal/logger"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriter(t *testing.T) {
+	logName := "tmpfile-writer.log"
+
+	// Test records are buffered until flush.
+	t.Run("buffered", func(t *testing.T) {
+		defer os.Remove(logName)
+
+		w, err := logger.NewWriter(logName, time.Hour, false)
+		assert.NoError(t, err)
+
+		assert.NoError(t, w.WriteLine("first\n"))
+
+		data, err := os.ReadFile(logName)
+		assert.NoError(t, err)
+		assert.Empty(t, string(data))
+
+		assert.NoError(t, w.Close())
+
+		data, err = os.ReadFile(logName)
+		assert.NoError(t, err)
+		assert.Equal(t, "first\n", string(data))
+	})
+
+	// Test records are flushed on the interval.
+	t.Run("flush interval", func(t *testing.T) {
+		defer os.Remove(logName)
+
+		w, err := logger.NewWriter(logName, 10*time.Millisecond, false)
+		assert.NoError(t, err)
+
+		defer w.Close()
+
+		assert.NoError(t, w.WriteLine("first\n"))
+
+		assert.Eventually(t, func() bool {
+			data, _ := os.ReadFile(logName)
+
+			return string(data) == "first\n"
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	// Test records are written at once in sync mode.
+	t.Run("sync", func(t *testing.T) {
+		defer os.Remove(logName)
+
+		w, err := logger.NewWriter(logName, 0, true)
+		assert.NoError(t, err)
+
+		defer w.Close()
+
+		assert.NoError(t, w.WriteLine("first\n"))
+
+		data, err := os.ReadFile(logName)
+		assert.NoError(t, err)
+		assert.Equal(t, "first\n", string(data))
+	})
+
+	t.Run("empty file name", func(t *testing.T) {
+		_, err := logger.NewWriter("", 0, true)
+		assert.ErrorIs(t, err, logger.ErrEmptyFileName)
+	})
+}